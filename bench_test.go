@@ -0,0 +1,105 @@
+package minima
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+var benchRoutes = []string{
+	"/",
+	"/users",
+	"/users/:id",
+	"/users/:id/posts/:post",
+	"/api/v1/health",
+	"/static/*filepath",
+}
+
+var benchPaths = []struct {
+	name string
+	path string
+}{
+	{"static", "/api/v1/health"},
+	{"param", "/users/42"},
+	{"params", "/users/42/posts/7"},
+	{"catchall", "/static/css/app.css"},
+}
+
+// A response writer that throws everything away so only the router is measured
+type discardWriter struct{ h http.Header }
+
+func (d *discardWriter) Header() http.Header         { return d.h }
+func (d *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (d *discardWriter) WriteHeader(int)             {}
+
+func noop(res *Response, req *Request) {}
+
+func benchTree(b *testing.B) *tree {
+	tr := NewTree()
+	for _, route := range benchRoutes {
+		if err := tr.InsertNode(route, noop); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return tr
+}
+
+func benchApp() *Minima {
+	app := Engine()
+	for _, route := range benchRoutes {
+		app.Get(route, noop)
+	}
+	return app
+}
+
+// Compares the lock-free lookup with the same lookup serialised behind a mutex, showing what
+// readers gain from not sharing a lock. The old map based tree is not part of the comparison
+func BenchmarkLookupParallel(b *testing.B) {
+	tr := benchTree(b)
+	var mu sync.Mutex
+	lookups := []struct {
+		name   string
+		lookup func(path string, ps *Params) *Node
+	}{
+		{"lock-free", tr.GetNode},
+		{"mutex", func(path string, ps *Params) *Node {
+			mu.Lock()
+			defer mu.Unlock()
+			return tr.GetNode(path, ps)
+		}},
+	}
+
+	for _, l := range lookups {
+		for _, p := range benchPaths {
+			b.Run(l.name+"/"+p.name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					ps := make(Params, 0, 4)
+					for pb.Next() {
+						ps = ps[:0]
+						if l.lookup(p.path, &ps) == nil {
+							b.Fatalf("%s not found", p.path)
+						}
+					}
+				})
+			})
+		}
+	}
+}
+
+func BenchmarkServeParallel(b *testing.B) {
+	app := benchApp()
+	for _, p := range benchPaths {
+		b.Run(p.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				w := &discardWriter{h: http.Header{}}
+				r := httptest.NewRequest("GET", p.path, nil)
+				for pb.Next() {
+					app.ServeHTTP(w, r)
+				}
+			})
+		})
+	}
+}
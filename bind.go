@@ -0,0 +1,455 @@
+package minima
+
+/**
+* Minima is a free and open source software under Mit license
+
+Copyright (c) 2024 gominima
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+* Authors @apoorvcodes @megatank58
+* Maintainers @Panquesito7 @savioxavier @Shubhaankar-Sharma @apoorvcodes @megatank58
+* Thank you for showing interest in minima and for this beautiful community
+*/
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+ * @info A value which couldn't be bound to a struct field
+ * @property {string} [Field] The dotted name of the field
+ * @property {string} [Source] Where the value came from, one of param, query, header, form or json
+ * @property {string} [Value] The raw value
+ * @property {string} [Message] What is wrong with the value
+ */
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+/**
+ * @info The field errors returned by Bind
+ */
+type FieldErrors []FieldError
+
+/**
+ * @info Joins the field errors into a single message
+ * @returns {string}
+ */
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, f := range e {
+		msgs[i] = fmt.Sprintf("%s %s", f.Field, f.Message)
+	}
+	return "invalid fields: " + strings.Join(msgs, ", ")
+}
+
+/**
+ * @info A request body which can't be read or decoded, which Invalid answers with a 400
+ * @property {error} [Err] The read or decode error
+ */
+type BodyError struct {
+	Err error
+}
+
+/**
+ * @info Returns the message of the read or decode error
+ * @returns {string}
+ */
+func (e *BodyError) Error() string {
+	return e.Err.Error()
+}
+
+/**
+ * @info Returns the read or decode error
+ * @returns {error}
+ */
+func (e *BodyError) Unwrap() error {
+	return e.Err
+}
+
+/**
+ * @info The struct tags read by Bind in order of precedence, the json body is decoded first
+ */
+var bindSources = []string{"param", "query", "header", "form"}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textUnmarshalType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+/**
+ * @info The state of a single Bind call
+ * @property {*Request} [req] The request to bind from
+ * @property {url.Values} [query] The parsed query params
+ * @property {url.Values} [form] The parsed form body, parsed on first use
+ * @property {FieldErrors} [errs] The collected field errors
+ */
+type binder struct {
+	req   *Request
+	query url.Values
+	form  url.Values
+	errs  FieldErrors
+}
+
+/**
+ * @info Populates a struct from the json body and the fields tagged with param, query, header or form, converting ints, bools, floats, time.Time, durations, slices and nested structs
+ * @param {interface{}} [dst] A pointer to the struct to populate
+ * @returns {error} FieldErrors for values which don't fit their fields, a BodyError for a body which can't be read or decoded
+ */
+func (r *Request) Bind(dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind destination must be a non nil pointer to a struct, got %T", dst)
+	}
+	b := &binder{req: r, query: r.ref.URL.Query()}
+	if err := b.bindJSON(dst); err != nil {
+		return err
+	}
+	if err := b.bindStruct(rv.Elem(), ""); err != nil {
+		return err
+	}
+	if len(b.errs) > 0 {
+		return b.errs
+	}
+	return nil
+}
+
+/**
+ * @info Decodes the json body, or the body of a registered decoder, into the fields tagged with json
+ * @param {interface{}} [dst] A pointer to the struct to populate
+ * @returns {error} The error of a body which can't be read or isn't json
+ */
+func (b *binder) bindJSON(dst interface{}) error {
+	media := mediaType(b.req.ref.Header.Get("Content-Type"))
+	var raw []byte
+	var err error
+	if isJSON(media) {
+		raw, err = b.req.RawBody()
+	} else if media != "application/x-www-form-urlencoded" && findDecoder(media, b.req.routeDecoders, b.req.decoders) != nil {
+		// Bodies of registered decoders bind through their map, by the same json tags
+		var body map[string]interface{}
+		if body, err = b.req.ParseBody(); err == nil && body != nil {
+			raw, err = json.Marshal(body)
+		}
+	}
+	if err != nil {
+		var bodyErr *BodyError
+		if errors.As(err, &bodyErr) {
+			return err
+		}
+		return &BodyError{Err: fmt.Errorf("can't read body: %w", err)}
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal(raw, dst); errors.As(err, &typeErr) {
+		// Unmarshal only reports the first mismatch, the fields are checked one by one for the others
+		errs := jsonTypeErrors(raw, reflect.TypeOf(dst).Elem(), "")
+		if len(errs) == 0 {
+			errs = FieldErrors{{Field: typeErr.Field, Source: "json", Message: "must be " + describeType(typeErr.Type)}}
+		}
+		b.errs = append(b.errs, errs...)
+	} else if err != nil {
+		return &BodyError{Err: fmt.Errorf("invalid json body: %w", err)}
+	}
+	return nil
+}
+
+/**
+ * @info Collects a field error for every json value which doesn't fit its struct field, descending into nested objects
+ * @param {[]byte} [raw] The json object
+ * @param {reflect.Type} [t] The struct type the object is decoded into
+ * @param {string} [prefix] The dotted name of the object
+ * @returns {FieldErrors}
+ */
+func jsonTypeErrors(raw []byte, t reflect.Type, prefix string) FieldErrors {
+	var object map[string]json.RawMessage
+	if json.Unmarshal(raw, &object) != nil {
+		return nil
+	}
+	var errs FieldErrors
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			// Embedded struct fields are promoted into the object
+			errs = append(errs, jsonTypeErrors(raw, ft, prefix)...)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		value, ok := object[name]
+		if !ok {
+			// Keys match their field regardless of case, as encoding/json does
+			for k, v := range object {
+				if strings.EqualFold(k, name) {
+					value, ok = v, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType && !reflect.PointerTo(ft).Implements(jsonUnmarshalType) && len(value) > 0 && value[0] == '{' {
+			errs = append(errs, jsonTypeErrors(value, ft, prefix+name+".")...)
+			continue
+		}
+		var typeErr *json.UnmarshalTypeError
+		if err := json.Unmarshal(value, reflect.New(sf.Type).Interface()); errors.As(err, &typeErr) {
+			field := prefix + name
+			if typeErr.Field != "" {
+				field += "." + typeErr.Field
+			}
+			errs = append(errs, FieldError{Field: field, Source: "json", Message: "must be " + describeType(typeErr.Type)})
+		}
+	}
+	return errs
+}
+
+/**
+ * @info Binds the tagged fields of a struct, descending into nested structs
+ * @param {reflect.Value} [v] The struct value
+ * @param {string} [prefix] The dotted name of the struct
+ * @returns {error}
+ */
+func (b *binder) bindStruct(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() && (!sf.Anonymous || sf.Type.Kind() != reflect.Struct) {
+			continue
+		}
+		fv := v.Field(i)
+		bound := false
+		for _, source := range bindSources {
+			name := sf.Tag.Get(source)
+			if name == "" || name == "-" || !sf.IsExported() {
+				continue
+			}
+			values, err := b.values(source, name)
+			if err != nil {
+				return err
+			}
+			bound = true
+			if len(values) == 0 {
+				continue
+			}
+			if err := setField(fv, values); err != nil {
+				b.errs = append(b.errs, FieldError{Field: prefix + name, Source: source, Value: values[0], Message: err.Error()})
+			}
+			break
+		}
+		if !bound && fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			name := sf.Name
+			if tag, _, _ := strings.Cut(sf.Tag.Get("json"), ","); tag != "" && tag != "-" {
+				name = tag
+			}
+			if sf.Anonymous {
+				name = ""
+			} else {
+				name += "."
+			}
+			if err := b.bindStruct(fv, prefix+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/**
+ * @info Gets the raw values of a key from a bind source
+ * @param {string} [source] One of param, query, header or form
+ * @param {string} [key] The key of the values
+ * @returns {[]string, error}
+ */
+func (b *binder) values(source string, key string) ([]string, error) {
+	switch source {
+	case "param":
+		for _, p := range b.req.Params {
+			if p.Key == key {
+				return []string{p.Value}, nil
+			}
+		}
+	case "query":
+		return b.query[key], nil
+	case "header":
+		return b.req.ref.Header.Values(key), nil
+	case "form":
+		if b.form == nil {
+			if _, err := b.req.FormParams(); err != nil {
+				return nil, &BodyError{Err: fmt.Errorf("invalid form body: %w", err)}
+			}
+			b.form = b.req.ref.PostForm
+		}
+		return b.form[key], nil
+	}
+	return nil, nil
+}
+
+/**
+ * @info Converts the raw values into a field, filling slices with every value
+ * @param {reflect.Value} [v] The field
+ * @param {[]string} [values] The raw values
+ * @returns {error}
+ */
+func setField(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Slice && !v.Addr().Type().Implements(textUnmarshalType) {
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(s.Index(i), value); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return setValue(v, values[0])
+}
+
+/**
+ * @info Converts a raw value into a field, leaving it zero for empty values
+ * @param {reflect.Value} [v] The field
+ * @param {string} [value] The raw value
+ * @returns {error}
+ */
+func setValue(v reflect.Value, value string) error {
+	if v.Kind() == reflect.Ptr {
+		if value == "" && v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		// Pointers are only set once the value converts
+		ptr := reflect.New(v.Type().Elem())
+		if err := setValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+	if value == "" && v.Kind() != reflect.String {
+		return nil
+	}
+	switch {
+	case v.Type() == timeType:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				v.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return errors.New("must be " + describeType(v.Type()))
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("must be " + describeType(v.Type()))
+		}
+		v.SetInt(int64(d))
+		return nil
+	case v.Addr().Type().Implements(textUnmarshalType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(value); err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(value, 10, v.Type().Bits()); err == nil {
+			v.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(value, 10, v.Type().Bits()); err == nil {
+			v.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var n float64
+		if n, err = strconv.ParseFloat(value, v.Type().Bits()); err == nil {
+			v.SetFloat(n)
+		}
+	default:
+		return fmt.Errorf("has unsupported type %s", v.Type())
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return errors.New("is out of range")
+	} else if err != nil {
+		return errors.New("must be " + describeType(v.Type()))
+	}
+	return nil
+}
+
+/**
+ * @info Describes the kind of value a type expects for field errors
+ * @param {reflect.Type} [t] The type of the field
+ * @returns {string}
+ */
+func describeType(t reflect.Type) string {
+	switch {
+	case t == timeType:
+		return "a time"
+	case t == durationType:
+		return "a duration"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a positive integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	}
+	return t.String()
+}
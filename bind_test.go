@@ -0,0 +1,229 @@
+package minima
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `json:"city" query:"city"`
+	Zip  int    `json:"zip"`
+}
+
+type bound struct {
+	ID      int           `json:"id" param:"id"`
+	Who     string        `json:"who" param:"who" query:"who" header:"X-Who" form:"who"`
+	Page    int           `query:"page"`
+	Tags    []string      `query:"tag"`
+	Nums    []int         `query:"n"`
+	Active  bool          `header:"X-Active"`
+	Since   time.Time     `query:"since"`
+	Timeout time.Duration `query:"timeout"`
+	Limit   *int          `query:"limit"`
+	Ratio   float64       `query:"ratio"`
+	Size    uint8         `query:"size"`
+	Address address       `json:"address"`
+}
+
+func TestBind(t *testing.T) {
+	five := 5
+	form := "application/x-www-form-urlencoded"
+	json := "application/json"
+	tests := []struct {
+		name        string
+		target      string
+		headers     map[string]string
+		contentType string
+		body        string
+		want        bound
+		errs        []string
+	}{
+		{
+			name:        "param over every other source",
+			target:      "/users/1/p?who=q",
+			headers:     map[string]string{"X-Who": "h"},
+			contentType: form,
+			body:        "who=f",
+			want:        bound{ID: 1, Who: "p"},
+		},
+		{
+			name:        "query over header and form",
+			target:      "/users/1?who=q",
+			headers:     map[string]string{"X-Who": "h"},
+			contentType: form,
+			body:        "who=f",
+			want:        bound{ID: 1, Who: "q"},
+		},
+		{
+			name:        "header over form",
+			target:      "/users/1",
+			headers:     map[string]string{"X-Who": "h"},
+			contentType: form,
+			body:        "who=f",
+			want:        bound{ID: 1, Who: "h"},
+		},
+		{
+			name:        "form",
+			target:      "/users/1",
+			contentType: form,
+			body:        "who=f",
+			want:        bound{ID: 1, Who: "f"},
+		},
+		{
+			name:        "json",
+			target:      "/users/1",
+			contentType: json,
+			body:        `{"who":"j","address":{"city":"Oslo","zip":150}}`,
+			want:        bound{ID: 1, Who: "j", Address: address{City: "Oslo", Zip: 150}},
+		},
+		{
+			name:        "tags override json",
+			target:      "/users/1?who=q&city=Paris",
+			contentType: json + "; charset=utf-8",
+			body:        `{"id":9,"who":"j","address":{"city":"Oslo","zip":150}}`,
+			want:        bound{ID: 1, Who: "q", Address: address{City: "Paris", Zip: 150}},
+		},
+		{
+			name:    "conversions",
+			target:  "/users/7?page=2&tag=a&tag=b&n=1&n=2&since=2024-01-02&timeout=1m30s&limit=5&ratio=0.5&size=255",
+			headers: map[string]string{"X-Active": "true"},
+			want: bound{
+				ID: 7, Page: 2, Tags: []string{"a", "b"}, Nums: []int{1, 2}, Active: true,
+				Since: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Timeout: 90 * time.Second,
+				Limit: &five, Ratio: 0.5, Size: 255,
+			},
+		},
+		{
+			name:   "rfc3339 time",
+			target: "/users/7?since=2024-01-02T15:04:05Z",
+			want:   bound{ID: 7, Since: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		},
+		{
+			name:   "empty values stay zero",
+			target: "/users/7?page=&limit=&since=",
+			want:   bound{ID: 7},
+		},
+		{
+			name:    "conversion errors",
+			target:  "/users/x?page=two&n=1&n=b&since=yesterday&timeout=soon&limit=many&ratio=half&size=256",
+			headers: map[string]string{"X-Active": "maybe"},
+			errs: []string{
+				"id param must be an integer",
+				"page query must be an integer",
+				"n query must be an integer",
+				"X-Active header must be a boolean",
+				"since query must be a time",
+				"timeout query must be a duration",
+				"limit query must be an integer",
+				"ratio query must be a number",
+				"size query is out of range",
+			},
+		},
+		{
+			name:        "every json type error",
+			target:      "/users/1",
+			contentType: json,
+			body:        `{"who":5,"Ratio":"half","address":{"city":"Oslo","zip":"abc"}}`,
+			errs: []string{
+				"who json must be a string",
+				"Ratio json must be a number",
+				"address.zip json must be an integer",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bound
+			var err error
+			bind := func(res *Response, req *Request) {
+				err = req.Bind(&got)
+			}
+			app := Engine()
+			app.Post("/users/:id", bind)
+			app.Post("/users/:id/:who", bind)
+			req := httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			app.ServeHTTP(httptest.NewRecorder(), req)
+
+			var fields FieldErrors
+			if tt.errs == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("got %+v\nwant %+v", got, tt.want)
+				}
+				return
+			}
+			if !errors.As(err, &fields) {
+				t.Fatalf("got %v, want field errors", err)
+			}
+			var msgs []string
+			for _, f := range fields {
+				msgs = append(msgs, f.Field+" "+f.Source+" "+f.Message)
+			}
+			if strings.Join(msgs, "\n") != strings.Join(tt.errs, "\n") {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(msgs, "\n"), strings.Join(tt.errs, "\n"))
+			}
+		})
+	}
+}
+
+func TestBindBodyErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"malformed json", "application/json", `{"who":`},
+		{"malformed form", "application/x-www-form-urlencoded", "who=%zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := Engine()
+			app.Post("/users/:id", func(res *Response, req *Request) {
+				var b bound
+				res.Invalid(req.Bind(&b))
+			})
+			req := httptest.NewRequest("POST", "/users/1", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != 400 {
+				t.Errorf("got %d %s", w.Code, w.Body.String())
+			}
+			// Errors about the whole body have no field or source to report
+			if body := w.Body.String(); strings.Contains(body, `"field"`) || strings.Contains(body, `"source"`) {
+				t.Errorf("empty field or source rendered: %s", body)
+			}
+		})
+	}
+}
+
+func TestBindDestination(t *testing.T) {
+	app := Engine()
+	var errs []error
+	app.Get("/", func(res *Response, req *Request) {
+		var b bound
+		var nilPtr *bound
+		errs = []error{req.Bind(b), req.Bind(nilPtr), req.Bind(new(int))}
+	})
+	serve(app, "GET", "/")
+	for i, err := range errs {
+		var fields FieldErrors
+		if err == nil || errors.As(err, &fields) {
+			t.Errorf("destination %d: got %v, want an error", i, err)
+		}
+	}
+}
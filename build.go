@@ -1,37 +1,167 @@
 package minima
 
-import "net/http"
+import (
+	"net/http"
+	"sync"
+)
 
 /**
  * @info Converts minima handler into middleware chain handler
  * @param {Handler} [h] The handler to convert
- * @param {map[string]string} [params] The handler params
+ * @param {Params} [params] The handler params
  * @return {func(http.Handler) http.Handler}
  */
-func build(h Handler, params map[string]string) func(http.Handler) http.Handler {
+func build(h Handler, params Params) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-			resp := response(w, req)
-			reqs := request(req)
-			reqs.Params = params
+			call := &middlewareCall{writer: trackedWriter{ResponseWriter: w}}
+			resp := &call.res
+			resp.reset(&call.writer, req)
+			reqs := request(req).share()
+			if params != nil {
+				reqs.Params = params
+			}
+			called := false
+			run := func() {
+				// A middleware that already answered the request ends the chain even without Abort
+				if called || resp.aborted || call.writer.wrote {
+					return
+				}
+				called = true
+				next.ServeHTTP(w, reqs.ref)
+			}
+			prev := reqs.next
+			reqs.next = run
 			h(resp, reqs)
-			next.ServeHTTP(w, req)
+			reqs.next = prev
+			// Middlewares that neither called Next nor aborted continue the chain once they return
+			run()
 		})
 	}
 }
 
+/**
+ * @info The per call state of a native middleware, kept together so a call is a single allocation
+ * @property {trackedWriter} [writer] The writer recording whether the middleware responded
+ * @property {Response} [res] The response handed to the middleware
+ */
+type middlewareCall struct {
+	writer trackedWriter
+	res    Response
+}
+
+/**
+ * @info A response writer that records whether a status or body has been written
+ * @property {http.ResponseWriter} [ResponseWriter] The wrapped net/http response instance
+ * @property {bool} [wrote] Whether a status or body has been written
+ */
+type trackedWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+/**
+ * @info Writes the status code and marks the response as written
+ * @param {int} [code] The status code
+ * @returns {}
+ */
+func (w *trackedWriter) WriteHeader(code int) {
+	w.wrote = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+/**
+ * @info Writes the body bytes and marks the response as written
+ * @param {[]byte} [b] The body bytes
+ * @returns {int, error}
+ */
+func (w *trackedWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(b)
+}
+
+/**
+ * @info Flushes the wrapped writer, which commits the response
+ * @returns {}
+ */
+func (w *trackedWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wrote = true
+		f.Flush()
+	}
+}
+
+/**
+ * @info Returns the wrapped writer for http.ResponseController
+ * @returns {http.ResponseWriter}
+ */
+func (w *trackedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+/**
+ * @info Converts minima handler into net/http middleware usable on routes, routers and groups
+ * @param {Handler} [h] The handler to convert
+ * @return {func(http.Handler) http.Handler}
+ */
+func Middleware(h Handler) func(http.Handler) http.Handler {
+	return build(h, nil)
+}
+
 /**
  * @info Converts minima handler into net/http handler func
  * @param {Handler} [h] The handler to convert
- * @param {map[string]string} [params] The handler params
+ * @param {Params} [params] The handler params
  * @return {http.Handler}
  */
-func buildHandler(h Handler, params map[string]string) http.Handler {
+func buildHandler(h Handler, params Params) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		resp := response(w, req)
 		reqs := request(req)
-		reqs.Params = params
+		if params != nil {
+			reqs.Params = params
+		}
 		h(resp, reqs)
-
 	})
 }
+
+/**
+ * @info Chains route middlewares around a handler reading its params from the shared request
+ * @param {Handler} [h] The route handler
+ * @param {[]func(http.Handler)http.Handler} [middlewares] The route middlewares
+ * @return {http.Handler}
+ */
+func routeHandler(h Handler, middlewares []func(http.Handler) http.Handler) http.Handler {
+	return chain(middlewares, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if rejectTooLarge(w, req) {
+			return
+		}
+		h(response(w, req), request(req))
+	}))
+}
+
+/**
+ * @info The request and response pair reused across requests, recycled once the route handler returns so handlers must not keep them for goroutines outliving the call
+ * @property {Request} [req] The pooled request instance
+ * @property {Response} [res] The pooled response instance
+ */
+type routeContext struct {
+	req Request
+	res Response
+}
+
+var contextPool = sync.Pool{
+	New: func() interface{} {
+		return &routeContext{req: Request{Params: make(Params, 0, 4)}}
+	},
+}
+
+/**
+ * @info Clears the pooled pair so it doesn't retain the finished request and returns it to the pool
+ * @returns {}
+ */
+func (c *routeContext) release() {
+	c.req = Request{Params: c.req.Params[:0]}
+	c.res = Response{}
+	contextPool.Put(c)
+}
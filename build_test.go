@@ -0,0 +1,144 @@
+package minima
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serve(h http.Handler, method string, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	tests := []struct {
+		name       string
+		middleware Handler
+		status     int
+		body       string
+		handled    bool
+	}{
+		{
+			name:       "falls through",
+			middleware: func(res *Response, req *Request) {},
+			status:     200,
+			body:       "handler",
+			handled:    true,
+		},
+		{
+			name: "calls next",
+			middleware: func(res *Response, req *Request) {
+				req.Next()
+				res.Send("+after")
+			},
+			status:  200,
+			body:    "handler+after",
+			handled: true,
+		},
+		{
+			name: "aborts",
+			middleware: func(res *Response, req *Request) {
+				res.Abort()
+			},
+			status: 200,
+		},
+		{
+			name: "responds without abort",
+			middleware: func(res *Response, req *Request) {
+				res.Status(401).Send("denied")
+			},
+			status: 401,
+			body:   "denied",
+		},
+		{
+			name: "writes status only",
+			middleware: func(res *Response, req *Request) {
+				res.Forbidden()
+			},
+			status: 403,
+		},
+		{
+			name: "responds then calls next",
+			middleware: func(res *Response, req *Request) {
+				res.Status(401).Send("denied")
+				req.Next()
+			},
+			status: 401,
+			body:   "denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled := false
+			app := Engine()
+			app.Use(tt.middleware)
+			app.Get("/", func(res *Response, req *Request) {
+				handled = true
+				res.Send("handler")
+			})
+
+			w := serve(app, "GET", "/")
+			if w.Code != tt.status || w.Body.String() != tt.body {
+				t.Errorf("got %d %q, want %d %q", w.Code, w.Body.String(), tt.status, tt.body)
+			}
+			if handled != tt.handled {
+				t.Errorf("handler ran = %v, want %v", handled, tt.handled)
+			}
+		})
+	}
+}
+
+func TestMiddlewareLocals(t *testing.T) {
+	app := Engine()
+	app.Use(func(res *Response, req *Request) {
+		req.Set("user", "ada")
+	})
+	app.Get("/", func(res *Response, req *Request) {
+		res.Send(req.Get("user").(string))
+	}, Middleware(func(res *Response, req *Request) {
+		req.Set("user", req.Get("user").(string)+"!")
+	}))
+
+	if w := serve(app, "GET", "/"); w.Body.String() != "ada!" {
+		t.Errorf("got %q, want %q", w.Body.String(), "ada!")
+	}
+}
+
+func TestRouteAllocs(t *testing.T) {
+	app := Engine()
+	app.Get("/health", noop)
+	app.Get("/users/:id", noop)
+	app.Version("2").Get("/versioned", noop)
+
+	for _, path := range []string{"/health", "/users/42", "/versioned"} {
+		w := &discardWriter{h: http.Header{}}
+		r := httptest.NewRequest("GET", path, nil)
+		r.Header.Set("API-Version", "2")
+		if allocs := testing.AllocsPerRun(100, func() { app.ServeHTTP(w, r) }); allocs != 0 {
+			t.Errorf("%s: %v allocs per request, want 0", path, allocs)
+		}
+	}
+}
+
+func TestChainedRouteRequestOutlivesCall(t *testing.T) {
+	var held *Request
+	app := Engine()
+	app.Get("/users/:id", func(res *Response, req *Request) {
+		held = req
+	}, func(next http.Handler) http.Handler {
+		return next
+	})
+
+	serve(app, "GET", "/users/1")
+	if held == nil {
+		t.Fatal("handler didn't run")
+	}
+	first := held
+	serve(app, "GET", "/users/2")
+	if got := first.Param("id"); got != "1" {
+		t.Errorf("request kept past the call was recycled, param id = %q", got)
+	}
+}
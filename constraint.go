@@ -0,0 +1,102 @@
+package minima
+
+/**
+* Minima is a free and open source software under Mit license
+
+Copyright (c) 2024 gominima
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+* Authors @apoorvcodes @megatank58
+* Maintainers @Panquesito7 @savioxavier @Shubhaankar-Sharma @apoorvcodes @megatank58
+* Thank you for showing interest in minima and for this beautiful community
+*/
+
+import (
+	"fmt"
+	"regexp"
+)
+
+/**
+ * @info Checks whether a captured param satisfies a route constraint
+ */
+type constraint func(value string) bool
+
+/**
+ * @info The builtin typed constraints usable as `:name<type>`
+ */
+var typedConstraints = map[string]constraint{
+	"int": func(v string) bool {
+		if v != "" && (v[0] == '-' || v[0] == '+') {
+			v = v[1:]
+		}
+		return isDigits(v)
+	},
+	"uint": isDigits,
+	"alpha": func(v string) bool {
+		for i := 0; i < len(v); i++ {
+			if c := v[i] | 0x20; c < 'a' || c > 'z' {
+				return false
+			}
+		}
+		return v != ""
+	},
+	"alnum": func(v string) bool {
+		for i := 0; i < len(v); i++ {
+			if c := v[i]; (c|0x20 < 'a' || c|0x20 > 'z') && (c < '0' || c > '9') {
+				return false
+			}
+		}
+		return v != ""
+	},
+	"uuid": regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+}
+
+/**
+ * @info Whether the string is made of ascii digits only
+ * @param {string} [v] The string to check
+ * @returns {bool}
+ */
+func isDigits(v string) bool {
+	for i := 0; i < len(v); i++ {
+		if v[i] < '0' || v[i] > '9' {
+			return false
+		}
+	}
+	return v != ""
+}
+
+/**
+ * @info Compiles a param constraint, either a builtin type or a regular expression
+ * @param {string} [expr] The constraint expression found between `<` and `>`
+ * @returns {constraint, error}
+ */
+func compileConstraint(expr string) (constraint, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("empty param constraint")
+	}
+	if c, ok := typedConstraints[expr]; ok {
+		return c, nil
+	}
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid param constraint <%s>: %w", expr, err)
+	}
+	return re.MatchString, nil
+}
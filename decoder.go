@@ -0,0 +1,126 @@
+package minima
+
+/**
+* Minima is a free and open source software under Mit license
+
+Copyright (c) 2024 gominima
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+* Authors @apoorvcodes @megatank58
+* Maintainers @Panquesito7 @savioxavier @Shubhaankar-Sharma @apoorvcodes @megatank58
+* Thank you for showing interest in minima and for this beautiful community
+*/
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+/**
+ * @info Decodes a request body into the map returned by GetBody and ParseBody
+ */
+type Decoder func(body io.Reader) (map[string]interface{}, error)
+
+/**
+ * @info The decoders every router starts with
+ */
+var builtinDecoders = map[string]Decoder{
+	"application/json":                  parseJSONData,
+	"application/x-www-form-urlencoded": parseFormData,
+}
+
+/**
+ * @info Registers the decoder of a media type, replacing the built in json and form decoders when given their types
+ * @param {string} [mediaType] The media type without params, e.g. application/xml or text/csv
+ * @param {Decoder} [decoder] The decoder
+ * @returns {*Router}
+ */
+func (r *Router) RegisterDecoder(mediaType string, decoder Decoder) *Router {
+	if r.body.decoders == nil {
+		r.body.decoders = make(map[string]Decoder)
+	}
+	r.body.decoders[strings.ToLower(mediaType)] = decoder
+	return r
+}
+
+/**
+ * @info Gets the media type of a Content-Type header without its params, lowercased
+ * @param {string} [contentType] The Content-Type header
+ * @returns {string}
+ */
+func mediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Malformed params shouldn't hide the media type itself
+		media, _, _ = strings.Cut(contentType, ";")
+		media = strings.ToLower(strings.TrimSpace(media))
+	}
+	return media
+}
+
+/**
+ * @info Finds the decoder of a media type, falling back from structured suffixes such as +json and +xml to their base type
+ * @param {string} [media] The media type
+ * @param {...map[string]Decoder} [decoders] The registered decoders, earlier ones win, may be nil
+ * @returns {Decoder}
+ */
+func findDecoder(media string, decoders ...map[string]Decoder) Decoder {
+	if media == "" {
+		return nil
+	}
+	for _, registered := range decoders {
+		if d, ok := registered[media]; ok {
+			return d
+		}
+	}
+	if d, ok := builtinDecoders[media]; ok {
+		return d
+	}
+	if i := strings.LastIndexByte(media, '+'); i >= 0 {
+		// application/vnd.api+json is decoded as application/json
+		if slash := strings.IndexByte(media, '/'); slash >= 0 && slash < i {
+			return findDecoder(media[:slash+1]+media[i+1:], decoders...)
+		}
+	}
+	return nil
+}
+
+/**
+ * @info Whether a media type is json, including +json vendor types
+ * @param {string} [media] The media type
+ * @returns {bool}
+ */
+func isJSON(media string) bool {
+	return media == "application/json" || strings.HasSuffix(media, "+json")
+}
+
+/**
+ * @info Whether a request carries a multipart form
+ * @param {*http.Request} [rq] The net/http request instance
+ * @returns {bool}
+ */
+func isMultipart(rq *http.Request) bool {
+	return mediaType(rq.Header.Get("Content-Type")) == "multipart/form-data"
+}
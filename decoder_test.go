@@ -0,0 +1,166 @@
+package minima
+
+import (
+	"bufio"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Decodes `key: value` lines
+func decodeLines(body io.Reader) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), ":"); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values, scanner.Err()
+}
+
+// Makes a decoder answering with its own name, so tests can tell which one was found
+func named(name string) Decoder {
+	return func(body io.Reader) (map[string]interface{}, error) {
+		return map[string]interface{}{"decoder": name}, nil
+	}
+}
+
+func TestMediaType(t *testing.T) {
+	tests := map[string]string{
+		"application/json":                  "application/json",
+		"application/json; charset=utf-8":   "application/json",
+		"Application/JSON; Charset=UTF-8":   "application/json",
+		"application/json; charset":         "application/json",
+		"multipart/form-data; boundary=abc": "multipart/form-data",
+		"":                                  "",
+	}
+	for header, want := range tests {
+		if got := mediaType(header); got != want {
+			t.Errorf("%q: got %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestFindDecoder(t *testing.T) {
+	own := map[string]Decoder{"application/vnd.acme+json": named("acme")}
+	registered := map[string]Decoder{
+		"application/xml":           named("xml"),
+		"application/vnd.acme+json": named("router acme"),
+	}
+	tests := []struct {
+		media    string
+		decoders []map[string]Decoder
+		want     string
+	}{
+		{"application/json", nil, "json"},
+		{"application/x-www-form-urlencoded", nil, "form"},
+		{"application/vnd.api+json", nil, "json"},
+		{"application/vnd.api+json", []map[string]Decoder{registered}, "json"},
+		{"application/vnd.acme+json", []map[string]Decoder{registered}, "router acme"},
+		{"application/vnd.acme+json", []map[string]Decoder{own, registered}, "acme"},
+		{"application/atom+xml", []map[string]Decoder{registered}, "xml"},
+		{"application/atom+xml", nil, ""},
+		{"text/csv", []map[string]Decoder{registered}, ""},
+		{"", []map[string]Decoder{registered}, ""},
+	}
+
+	for _, tt := range tests {
+		d := findDecoder(tt.media, tt.decoders...)
+		got := ""
+		switch {
+		case d == nil:
+		case reflect.ValueOf(d).Pointer() == reflect.ValueOf(Decoder(parseJSONData)).Pointer():
+			got = "json"
+		case reflect.ValueOf(d).Pointer() == reflect.ValueOf(Decoder(parseFormData)).Pointer():
+			got = "form"
+		default:
+			body, _ := d(nil)
+			got = body["decoder"].(string)
+		}
+		if got != tt.want {
+			t.Errorf("%s with %d maps: got %q, want %q", tt.media, len(tt.decoders), got, tt.want)
+		}
+	}
+}
+
+func TestCustomDecoder(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+	reads := map[string]func(req *Request) string{
+		"GetBody": func(req *Request) string {
+			body := req.GetBody()
+			return body["name"].(string) + "," + body["role"].(string)
+		},
+		"Bind": func(req *Request) string {
+			var u user
+			if err := req.Bind(&u); err != nil {
+				return err.Error()
+			}
+			return u.Name + "," + u.Role
+		},
+	}
+	apps := map[string]func(h Handler) *Minima{
+		"engine": func(h Handler) *Minima {
+			app := Engine().RegisterDecoder("text/x-lines", decodeLines)
+			app.Post("/users", h)
+			return app
+		},
+		"mounted router": func(h Handler) *Minima {
+			child := NewRouter().RegisterDecoder("text/x-lines", decodeLines)
+			child.Post("/", h)
+			return Engine().Mount("/users", child)
+		},
+		"host router": func(h Handler) *Minima {
+			child := NewRouter().RegisterDecoder("text/x-lines", decodeLines)
+			child.Post("/users", h)
+			return Engine().Host("api.example.com", child)
+		},
+	}
+
+	for name, build := range apps {
+		for by, read := range reads {
+			t.Run(name+" "+by, func(t *testing.T) {
+				app := build(func(res *Response, req *Request) {
+					res.Send(read(req))
+				})
+				req := httptest.NewRequest("POST", "/users", strings.NewReader("name: ada\nrole: admin\n"))
+				req.Host = "api.example.com"
+				req.Header.Set("Content-Type", "Text/X-Lines; charset=utf-8")
+				w := httptest.NewRecorder()
+				app.ServeHTTP(w, req)
+				if w.Body.String() != "ada,admin" {
+					t.Errorf("got %d %q", w.Code, w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestMountedDecodersKeepTheRouters(t *testing.T) {
+	child := NewRouter().RegisterDecoder("application/vnd.acme+json", named("child"))
+	child.Post("/", func(res *Response, req *Request) {
+		res.Send(req.GetBody()["decoder"].(string))
+	})
+	app := Engine().RegisterDecoder("application/xml", named("app"))
+	app.Mount("/child", child)
+	// Registered after the mount, the child's routes don't see it
+	child.RegisterDecoder("application/xml", named("late"))
+
+	for media, want := range map[string]string{
+		"application/vnd.acme+json": "child",
+		"application/atom+xml":      "app",
+	} {
+		req := httptest.NewRequest("POST", "/child", strings.NewReader("<a/>"))
+		req.Header.Set("Content-Type", media)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		if w.Body.String() != want {
+			t.Errorf("%s: got %q, want %q", media, w.Body.String(), want)
+		}
+	}
+}
@@ -1,13 +1,33 @@
 package minima
 
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
 /**
  * @info The minima group structure
  * @property {[]cacheroute} [route] The array of cached routes
  * @property {[string} [prefix] The group prefix
+ * @property {[]func(http.Handler)http.Handler} [middlewares] The middlewares wrapping every group route
+ * @property {[]*Group} [children] The nested groups inheriting the prefix and middlewares
+ * @property {string} [version] The api version the group routes are served for
+ * @property {*Router} [router] The router routes are added to right away, nil for groups merged later
+ * @property {*Group} [parent] The group a router bound group was created from
+ * @property {atomic.Pointer[[]func(http.Handler)http.Handler]} [stack] The inherited and own middlewares of a router bound group, read when serving
+ * @property {[]error} [errors] The registration errors reported by the router the group is merged into
  */
 type Group struct {
-	route  []*cacheRoute
-	prefix string
+	route       []*cacheRoute
+	prefix      string
+	middlewares []func(http.Handler) http.Handler
+	children    []*Group
+	version     string
+	router      *Router
+	parent      *Group
+	stack       atomic.Pointer[[]func(http.Handler) http.Handler]
+	errors      []error
 }
 
 /**
@@ -21,22 +41,156 @@ func NewGroup(prefix string) *Group {
 	}
 }
 
-func (g *Group) register(method string, path string, handler Handler) {
-	g.route = append(g.route, &cacheRoute{
-		method:  method,
-		path:    g.prefix + path,
-		handler: handler,
+/**
+ * @info Creates a nested group inheriting the group prefix and middlewares
+ * @param {string} [prefix] The prefix appended to the group prefix
+ * @return {*Group}
+ */
+func (g *Group) Group(prefix string) *Group {
+	child := NewGroup(g.prefix + prefix)
+	child.version = g.version
+	if g.router != nil {
+		// Routes are added right away, so the middlewares are resolved from the parent when serving
+		child.router = g.router
+		child.parent = g
+		g.router.mu.Lock()
+		g.children = append(g.children, child)
+		child.refresh()
+		g.router.mu.Unlock()
+		return child
+	}
+	g.children = append(g.children, child)
+	return child
+}
+
+/**
+ * @info Creates a nested group whose routes are only served for an api version
+ * @param {string} [version] The api version, e.g. 2 or v2
+ * @return {*Group}
+ */
+func (g *Group) Version(version string) *Group {
+	child := g.Group("")
+	child.version = normalizeVersion(version)
+	return child
+}
+
+func (g *Group) register(method string, path string, handler Handler, middlewares ...func(http.Handler) http.Handler) {
+	route := &cacheRoute{
+		method:      method,
+		path:        g.prefix + path,
+		handler:     handler,
+		middlewares: middlewares,
+		version:     g.version,
+	}
+	if g.router != nil {
+		route.group = g
+		g.router.report(g.router.add(route))
+		return
+	}
+	g.route = append(g.route, route)
+}
+
+/**
+ * @info Rebuilds the middleware stack of a router bound group and its nested groups, the router mutex must be held
+ * @returns {}
+ */
+func (g *Group) refresh() {
+	var stack []func(http.Handler) http.Handler
+	if g.parent != nil {
+		if parent := g.parent.stack.Load(); parent != nil {
+			stack = append(stack, *parent...)
+		}
+	}
+	stack = append(stack, g.middlewares...)
+	g.stack.Store(&stack)
+	for _, child := range g.children {
+		child.refresh()
+	}
+}
+
+/**
+ * @info Wraps a router bound group route with the group middlewares current when serving, so Use applies to routes added before it
+ * @param {http.Handler} [next] The route handler
+ * @returns {http.Handler}
+ */
+func (g *Group) live(next http.Handler) http.Handler {
+	type built struct {
+		stack   *[]func(http.Handler) http.Handler
+		handler http.Handler
+	}
+	var cache atomic.Pointer[built]
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stack := g.stack.Load()
+		b := cache.Load()
+		if b == nil || b.stack != stack {
+			b = &built{stack: stack, handler: next}
+			if stack != nil {
+				b.handler = chain(*stack, next)
+			}
+			cache.Store(b)
+		}
+		b.handler.ServeHTTP(w, r)
 	})
 }
 
+/**
+ * @info Names the last added group route so urls can be generated for it
+ * @param {string} [name] The route name
+ * @returns {*Group}
+ */
+func (g *Group) Name(name string) *Group {
+	if g.router != nil {
+		g.router.Name(name)
+		return g
+	}
+	if len(g.route) == 0 {
+		// Groups have no router to report to until they are merged
+		g.errors = append(g.errors, fmt.Errorf("name %s must follow a registered route", name))
+		return g
+	}
+	g.route[len(g.route)-1].name = name
+	return g
+}
+
+/**
+ * @info Takes the registration errors of the group and its nested groups so they are reported once
+ * @returns {[]error}
+ */
+func (g *Group) takeErrors() []error {
+	errs := g.errors
+	g.errors = nil
+	for _, child := range g.children {
+		errs = append(errs, child.takeErrors()...)
+	}
+	return errs
+}
+
+/**
+ * @info Injects net/http middleware that only wraps the group routes
+ * @param {...func(http.Handler)http.Handler} [handler] The handler stack to append
+ * @returns {*Group}
+ */
+func (g *Group) Use(handler ...func(http.Handler) http.Handler) *Group {
+	if g.router != nil {
+		g.router.mu.Lock()
+		g.middlewares = append(g.middlewares, handler...)
+		g.refresh()
+		g.router.mu.Unlock()
+		return g
+	}
+	g.middlewares = append(g.middlewares, handler...)
+	return g
+}
+
 /**
  * @info Adds route with Get method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Router}
  */
-func (g *Group) Get(path string, handler Handler) *Group {
-	g.register("GET", path, handler)
+func (g *Group) Get(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	g.register("GET", path, handler, middlewares...)
 	return g
 }
 
@@ -44,10 +198,11 @@ func (g *Group) Get(path string, handler Handler) *Group {
  * @info Adds route with Post method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Group}
  */
-func (g *Group) Post(path string, handler Handler) *Group {
-	g.register("POST", path, handler)
+func (g *Group) Post(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	g.register("POST", path, handler, middlewares...)
 	return g
 }
 
@@ -55,10 +210,11 @@ func (g *Group) Post(path string, handler Handler) *Group {
  * @info Adds route with Put method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Group}
  */
-func (g *Group) Put(path string, handler Handler) *Group {
-	g.register("PUT", path, handler)
+func (g *Group) Put(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	g.register("PUT", path, handler, middlewares...)
 	return g
 }
 
@@ -66,20 +222,23 @@ func (g *Group) Put(path string, handler Handler) *Group {
  * @info Adds route with Patch method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Group}
  */
-func (g *Group) Patch(path string, handler Handler) {
-	g.register("PATCH", path, handler)
+func (g *Group) Patch(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	g.register("PATCH", path, handler, middlewares...)
+	return g
 }
 
 /**
  * @info Adds route with Options method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Group}
  */
-func (g *Group) Options(path string, handler Handler) *Group {
-	g.register("OPTIONS", path, handler)
+func (g *Group) Options(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	g.register("OPTIONS", path, handler, middlewares...)
 	return g
 }
 
@@ -87,10 +246,50 @@ func (g *Group) Options(path string, handler Handler) *Group {
  * @info Adds route with Head method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @returns {*Group}
+ */
+func (g *Group) Head(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	g.register("HEAD", path, handler, middlewares...)
+	return g
+}
+
+/**
+ * @info Adds route with any method, including extension methods such as PROPFIND, MKCOL or QUERY
+ * @param {string} [method] The route method
+ * @param {string} [path] The route path
+ * @param {Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Group}
  */
-func (g *Group) Head(path string, handler Handler) *Group {
-	g.register("HEAD", path, handler)
+func (g *Group) Handle(method string, path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	g.register(method, path, handler, middlewares...)
+	return g
+}
+
+/**
+ * @info Adds route with every standard method
+ * @param {string} [path] The route path
+ * @param {Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @returns {*Group}
+ */
+func (g *Group) Any(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	return g.Match(methods, path, handler, middlewares...)
+}
+
+/**
+ * @info Adds route with each of the given methods
+ * @param {[]string} [methods] The route methods
+ * @param {string} [path] The route path
+ * @param {Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @returns {*Group}
+ */
+func (g *Group) Match(methods []string, path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	for _, method := range methods {
+		g.register(method, path, handler, middlewares...)
+	}
 	return g
 }
 
@@ -98,17 +297,29 @@ func (g *Group) Head(path string, handler Handler) *Group {
  * @info Adds route with Delete method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Group}
  */
-func (g *Group) Delete(path string, handler Handler) *Group {
-	g.register("DELETE", path, handler)
+func (g *Group) Delete(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Group {
+	g.register("DELETE", path, handler, middlewares...)
 	return g
 }
 
 /**
- * @info Returns all routes for the group
+ * @info Returns all routes for the group and its nested groups, wrapped with the group middlewares
  * @return {[]cachRoute}
  */
 func (g *Group) GetGroupRoutes() []*cacheRoute {
-	return g.route
+	if len(g.middlewares) == 0 && len(g.children) == 0 {
+		return g.route
+	}
+	routes := make([]*cacheRoute, 0, len(g.route))
+	routes = append(routes, g.route...)
+	for _, child := range g.children {
+		routes = append(routes, child.GetGroupRoutes()...)
+	}
+	for i, v := range routes {
+		routes[i] = v.wrap(g.middlewares)
+	}
+	return routes
 }
@@ -0,0 +1,112 @@
+package minima
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func deny(res *Response, req *Request) {
+	res.Status(401).Send("denied")
+	res.Abort()
+}
+
+func ok(res *Response, req *Request) {
+	res.Send("ok")
+}
+
+func TestGroupMiddlewareOrder(t *testing.T) {
+	groups := map[string]func(app *Minima) (*Group, func()){
+		"detached": func(app *Minima) (*Group, func()) {
+			g := NewGroup("")
+			return g, func() { app.UseGroup(g) }
+		},
+		"versioned": func(app *Minima) (*Group, func()) {
+			return app.Version("2"), func() {}
+		},
+	}
+	orders := map[string]func(g *Group){
+		"use first": func(g *Group) {
+			g.Use(Middleware(deny))
+			g.Get("/a", ok)
+			g.Group("/b").Get("/c", ok)
+		},
+		"use last": func(g *Group) {
+			g.Get("/a", ok)
+			g.Group("/b").Get("/c", ok)
+			g.Use(Middleware(deny))
+		},
+	}
+
+	for gname, newGroup := range groups {
+		for oname, register := range orders {
+			t.Run(gname+"/"+oname, func(t *testing.T) {
+				app := Engine()
+				g, done := newGroup(app)
+				register(g)
+				done()
+
+				for _, path := range []string{"/a", "/b/c"} {
+					req := httptest.NewRequest("GET", path, nil)
+					req.Header.Set("API-Version", "2")
+					w := httptest.NewRecorder()
+					app.ServeHTTP(w, req)
+					if w.Code != http.StatusUnauthorized {
+						t.Errorf("%s: got %d, want 401", path, w.Code)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGroupNameWithoutRoute(t *testing.T) {
+	app := Engine()
+	g := NewGroup("/api")
+	g.Name("orphan")
+	g.Get("/a", ok).Name("a")
+	app.UseGroup(g)
+	if errs := app.Errors(); len(errs) != 1 {
+		t.Fatalf("got errors %v, want one", errs)
+	}
+	if url, err := app.URL("a", nil); err != nil || url != "/api/a" {
+		t.Errorf("got %q %v", url, err)
+	}
+
+	router := NewRouter()
+	router.Group("/v1").Name("orphan")
+	app.Mount("/x", router)
+	if errs := app.Errors(); len(errs) != 2 {
+		t.Errorf("mounted group: got errors %v, want two", errs)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("strict mode didn't panic")
+		}
+	}()
+	strict := Engine().Strict(true)
+	g = NewGroup("")
+	g.Name("orphan")
+	strict.UseGroup(g)
+}
+
+func TestVersionGroupMiddlewareCount(t *testing.T) {
+	app := Engine()
+	v2 := app.Version("2")
+	v2.Get("/bare", ok)
+	if n := app.Routes()[0].Middlewares; n != 0 {
+		t.Errorf("bare version route reports %d middlewares", n)
+	}
+
+	v2.Use(Middleware(deny))
+	nested := v2.Group("/admin")
+	nested.Use(Middleware(deny))
+	nested.Get("/users", ok)
+	for _, route := range app.Routes() {
+		want := map[string]int{"/bare": 1, "/admin/users": 2}[route.Pattern]
+		if route.Middlewares != want {
+			t.Errorf("%s: got %d middlewares, want %d", route.Pattern, route.Middlewares, want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package minima
+
+/**
+* Minima is a free and open source software under Mit license
+
+Copyright (c) 2024 gominima
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+* Authors @apoorvcodes @megatank58
+* Maintainers @Panquesito7 @savioxavier @Shubhaankar-Sharma @apoorvcodes @megatank58
+* Thank you for showing interest in minima and for this beautiful community
+*/
+
+import (
+	"fmt"
+	"strings"
+)
+
+/**
+ * @info A router serving only the requests whose host matches a pattern
+ * @property {string} [pattern] The host pattern, e.g. api.example.com or :tenant.example.com
+ * @property {[]string} [labels] The dot separated labels of the pattern
+ * @property {int} [params] The number of param labels
+ * @property {*Router} [router] The router holding the method trees of the host
+ */
+type host struct {
+	pattern string
+	labels  []string
+	params  int
+	router  *Router
+}
+
+/**
+ * @info Matches a hostname label by label, appending the captured params
+ * @param {string} [hostname] The lowercased hostname without port
+ * @param {*Params} [ps] The params to append to
+ * @returns {bool}
+ */
+func (h *host) match(hostname string, ps *Params) bool {
+	if strings.Count(hostname, ".") != len(h.labels)-1 {
+		return false
+	}
+	mark := len(*ps)
+	for _, label := range h.labels {
+		part := hostname
+		if end := strings.IndexByte(hostname, '.'); end >= 0 {
+			part, hostname = hostname[:end], hostname[end+1:]
+		}
+		if label[0] == ':' && part != "" {
+			*ps = append(*ps, Param{Key: label[1:], Value: part})
+		} else if label != part {
+			*ps = (*ps)[:mark]
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * @info Strips the port and case from a request host
+ * @param {string} [hostport] The request host
+ * @returns {string}
+ */
+func hostname(hostport string) string {
+	if i := strings.LastIndexByte(hostport, ':'); i > strings.LastIndexByte(hostport, ']') && isDigits(hostport[i+1:]) {
+		hostport = hostport[:i]
+	}
+	return strings.ToLower(strings.TrimSuffix(hostport, "."))
+}
+
+/**
+ * @info Serves the routes of a router only for requests whose host matches the pattern, :name labels capture into the params
+ * @param {string} [pattern] The host pattern, e.g. api.example.com or :tenant.example.com
+ * @param {*Router} [router] The router to serve for the host
+ * @returns {*Router}
+ */
+func (r *Router) Host(pattern string, router *Router) *Router {
+	pattern = hostname(pattern)
+	h, err := r.hostFor(pattern)
+	if err != nil {
+		r.report(err)
+		return r
+	}
+	h.router.Mount("", router)
+	if router.notfound != nil {
+		// A root mount answers every path of the host the routes don't
+		h.router.addMounts([]*mount{{prefix: "", notfound: chain(router.middlewares, router.notfound)}})
+	}
+	h.router.mu.Lock()
+	errs := h.router.errors
+	h.router.errors = nil
+	h.router.mu.Unlock()
+	r.mu.Lock()
+	r.errors = append(r.errors, errs...)
+	r.mu.Unlock()
+	return r
+}
+
+/**
+ * @info Finds the host of a pattern, publishing a new one in a copy of the hosts when there is none
+ * @param {string} [pattern] The normalized host pattern
+ * @returns {*host, error}
+ */
+func (r *Router) hostFor(pattern string) (*host, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.hostList()
+	for _, v := range old {
+		if v.pattern == pattern {
+			return v, nil
+		}
+	}
+	h := &host{pattern: pattern, labels: strings.Split(pattern, "."), router: NewRouter()}
+	for _, label := range h.labels {
+		if label == "" || label == ":" {
+			return nil, fmt.Errorf("host %s has an empty label", pattern)
+		}
+		if label[0] == ':' {
+			h.params++
+		}
+	}
+	h.router.isCache = false
+	h.router.strict = r.strict
+	h.router.redirectTrailingSlash = r.redirectTrailingSlash
+	h.router.redirectFixedPath = r.redirectFixedPath
+	h.router.CaseInsensitive(r.caseInsensitive)
+	// Exact hosts are tried before the ones capturing params
+	i := len(old)
+	for i > 0 && old[i-1].params > h.params {
+		i--
+	}
+	hosts := make([]*host, 0, len(old)+1)
+	hosts = append(append(append(hosts, old[:i]...), h), old[i:]...)
+	r.hosts.Store(&hosts)
+	return h, nil
+}
+
+/**
+ * @info Returns the routers selected by the request host
+ * @returns {[]*host}
+ */
+func (r *Router) hostList() []*host {
+	if hosts := r.hosts.Load(); hosts != nil {
+		return *hosts
+	}
+	return nil
+}
+
+/**
+ * @info Finds the router serving the request host, appending the captured params
+ * @param {string} [hostport] The request host
+ * @param {*Params} [ps] The params to append to
+ * @returns {*Router}
+ */
+func (r *Router) hostRouter(hostport string, ps *Params) *Router {
+	hosts := r.hostList()
+	if len(hosts) == 0 {
+		return r
+	}
+	name := hostname(hostport)
+	for _, h := range hosts {
+		if h.match(name, ps) {
+			return h.router
+		}
+	}
+	return r
+}
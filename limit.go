@@ -0,0 +1,325 @@
+package minima
+
+/**
+* Minima is a free and open source software under Mit license
+
+Copyright (c) 2024 gominima
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+* Authors @apoorvcodes @megatank58
+* Maintainers @Panquesito7 @savioxavier @Shubhaankar-Sharma @apoorvcodes @megatank58
+* Thank you for showing interest in minima and for this beautiful community
+*/
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+/**
+ * @info The in-memory threshold of multipart forms when none is set, matching net/http
+ */
+const defaultMultipartMemory = 32 << 20
+
+/**
+ * @info Flags of the body settings a router was given explicitly
+ */
+const (
+	setBodyLimit uint8 = 1 << iota
+	setMultipartMemory
+	setMultipartSpill
+)
+
+/**
+ * @info The body settings of a router, the routes it hands to Mount and Host carry them so a mounted router keeps its own
+ * @property {int64} [limit] The maximum request body size in bytes, zero for no limit
+ * @property {int64} [maxMemory] The in-memory threshold of multipart forms
+ * @property {bool} [noSpill] Whether multipart files over the threshold fail instead of spilling to temp files
+ * @property {map[string]Decoder} [decoders] The body decoders by media type
+ * @property {uint8} [set] The settings given explicitly, the others come from the router serving the route
+ */
+type bodySettings struct {
+	limit     int64
+	maxMemory int64
+	noSpill   bool
+	decoders  map[string]Decoder
+	set       uint8
+}
+
+/**
+ * @info Layers route settings over the ones of the router they are mounted from, the route's own win
+ * @param {*bodySettings} [base] The settings of the mounted router
+ * @returns {*bodySettings} nil when neither has any
+ */
+func (s *bodySettings) over(base *bodySettings) *bodySettings {
+	if s == nil {
+		s = &bodySettings{}
+	}
+	if base.set == 0 && len(base.decoders) == 0 {
+		if s.set == 0 && len(s.decoders) == 0 {
+			return nil
+		}
+		return s
+	}
+	c := *s
+	if c.set&setBodyLimit == 0 && base.set&setBodyLimit != 0 {
+		c.limit = base.limit
+	}
+	if c.set&setMultipartMemory == 0 && base.set&setMultipartMemory != 0 {
+		c.maxMemory = base.maxMemory
+	}
+	if c.set&setMultipartSpill == 0 && base.set&setMultipartSpill != 0 {
+		c.noSpill = base.noSpill
+	}
+	c.set |= base.set
+	if len(base.decoders) > 0 {
+		// A copy, so decoders registered on the mounted router later don't race with requests
+		c.decoders = make(map[string]Decoder, len(base.decoders)+len(s.decoders))
+		for k, v := range base.decoders {
+			c.decoders[k] = v
+		}
+		for k, v := range s.decoders {
+			c.decoders[k] = v
+		}
+	}
+	return &c
+}
+
+/**
+ * @info Resolves the settings of a route, taking the router's where the route has none of its own
+ * @param {*bodySettings} [route] The settings the route carries, may be nil
+ * @returns {int64, int64, bool} The body limit, the multipart in-memory threshold and whether spilling is disabled
+ */
+func (s *bodySettings) resolve(route *bodySettings) (int64, int64, bool) {
+	limit, maxMemory, noSpill := s.limit, s.maxMemory, s.noSpill
+	if route == nil {
+		return limit, maxMemory, noSpill
+	}
+	if route.set&setBodyLimit != 0 {
+		limit = route.limit
+	}
+	if route.set&setMultipartMemory != 0 {
+		maxMemory = route.maxMemory
+	}
+	if route.set&setMultipartSpill != 0 {
+		noSpill = route.noSpill
+	}
+	return limit, maxMemory, noSpill
+}
+
+/**
+ * @info A request body capped by a body limit, keeping the original so a route can set its own limit
+ * @property {io.ReadCloser} [ReadCloser] The limited body
+ * @property {io.ReadCloser} [orig] The original body
+ * @property {int64} [limit] The maximum body size in bytes
+ * @property {limitWriter} [writer] The response writer handed to the handler, which answers 413 once the limit is hit
+ */
+type limitedBody struct {
+	io.ReadCloser
+	orig   io.ReadCloser
+	limit  int64
+	writer limitWriter
+}
+
+/**
+ * @info A response writer dropping what the handler writes after the body limit was hit, so the 413 stays the whole response
+ * @property {http.ResponseWriter} [ResponseWriter] The wrapped net/http response instance
+ * @property {bool} [rejected] Whether the 413 was sent
+ */
+type limitWriter struct {
+	http.ResponseWriter
+	rejected bool
+}
+
+/**
+ * @info Writes the status code unless the request was rejected
+ * @param {int} [code] The status code
+ * @returns {}
+ */
+func (w *limitWriter) WriteHeader(code int) {
+	if !w.rejected {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+/**
+ * @info Writes the body bytes unless the request was rejected
+ * @param {[]byte} [b] The body bytes
+ * @returns {int, error}
+ */
+func (w *limitWriter) Write(b []byte) (int, error) {
+	if w.rejected {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+/**
+ * @info Flushes the wrapped writer
+ * @returns {}
+ */
+func (w *limitWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok && !w.rejected {
+		f.Flush()
+	}
+}
+
+/**
+ * @info Returns the wrapped writer for http.ResponseController
+ * @returns {http.ResponseWriter}
+ */
+func (w *limitWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+/**
+ * @info Caps the request body, replacing the limit set earlier in the chain
+ * @param {http.ResponseWriter} [w] The net/http response instance
+ * @param {*http.Request} [rq] The net/http request instance
+ * @param {int64} [limit] The maximum body size in bytes, zero or less for no limit
+ * @returns {http.ResponseWriter} The writer to hand to the handler
+ */
+func limitBody(w http.ResponseWriter, rq *http.Request, limit int64) http.ResponseWriter {
+	if rq.Body == nil || rq.Body == http.NoBody {
+		return w
+	}
+	orig := rq.Body
+	if lb, ok := orig.(*limitedBody); ok {
+		orig = lb.orig
+	}
+	if limit <= 0 {
+		rq.Body = orig
+		return w
+	}
+	lb := &limitedBody{ReadCloser: http.MaxBytesReader(w, orig, limit), orig: orig, limit: limit}
+	lb.writer.ResponseWriter = w
+	rq.Body = lb
+	return &lb.writer
+}
+
+/**
+ * @info Answers 413 when a read through the request accessors went past the body limit, later writes of the handler are dropped
+ * @param {error} [err] The read error
+ * @returns {}
+ */
+func (r *Request) overflow(err error) {
+	var tooLarge *http.MaxBytesError
+	lb, ok := r.ref.Body.(*limitedBody)
+	if !ok || lb.writer.rejected || !errors.As(err, &tooLarge) || tooLarge.Limit != lb.limit {
+		return
+	}
+	response(&lb.writer, r.ref).Error(http.StatusRequestEntityTooLarge, "Payload too large")
+	lb.writer.rejected = true
+}
+
+/**
+ * @info Answers 413 before the route handler runs when the Content-Length is already over the body limit
+ * @param {http.ResponseWriter} [w] The net/http response instance
+ * @param {*http.Request} [rq] The net/http request instance
+ * @returns {bool} Whether the request was rejected
+ */
+func rejectTooLarge(w http.ResponseWriter, rq *http.Request) bool {
+	if lb, ok := rq.Body.(*limitedBody); ok && rq.ContentLength > lb.limit {
+		response(w, rq).Error(http.StatusRequestEntityTooLarge, "Payload too large")
+		return true
+	}
+	return false
+}
+
+/**
+ * @info Creates middleware capping the body of the routes it wraps, overriding the global body limit
+ * @param {int64} [limit] The maximum body size in bytes, zero or less for no limit
+ * @returns {func(http.Handler)http.Handler}
+ */
+func BodyLimit(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, rq *http.Request) {
+			next.ServeHTTP(limitBody(w, rq, limit), rq)
+		})
+	}
+}
+
+/**
+ * @info Caps the body of every request, answering 413 when the Content-Length is over the limit or a body accessor such as GetBody reads past it, reading the raw body yourself fails with an *http.MaxBytesError instead
+ * @param {int64} [limit] The maximum body size in bytes, zero or less for no limit
+ * @returns {*Router}
+ */
+func (r *Router) BodyLimit(limit int64) *Router {
+	r.body.limit = limit
+	r.body.set |= setBodyLimit
+	return r
+}
+
+/**
+ * @info Sets how much of a multipart form is kept in memory, larger files spill to os.TempDir(), which net/http offers no per-request directory for, so the TMPDIR environment variable picks it
+ * @param {int64} [maxMemory] The in-memory threshold in bytes
+ * @returns {*Router}
+ */
+func (r *Router) MultipartMemory(maxMemory int64) *Router {
+	r.body.maxMemory = maxMemory
+	r.body.set |= setMultipartMemory
+	return r
+}
+
+/**
+ * @info Sets whether multipart files over the in-memory threshold spill to temp files, when disabled the whole multipart body is capped at the threshold while reading and larger ones fail with an *http.MaxBytesError, so nothing is written to disk
+ * @param {bool} [enabled] Whether files spill to temp files
+ * @returns {*Router}
+ */
+func (r *Router) MultipartSpill(enabled bool) *Router {
+	r.body.noSpill = !enabled
+	r.body.set |= setMultipartSpill
+	return r
+}
+
+/**
+ * @info Parses the multipart form once with the configured in-memory threshold
+ * @returns {error}
+ */
+func (r *Request) parseMultipart() error {
+	if r.ref.MultipartForm != nil {
+		return nil
+	}
+	maxMemory := r.maxMemory
+	if maxMemory <= 0 {
+		maxMemory = defaultMultipartMemory
+	}
+	body := r.ref.Body
+	if r.noSpill && body != nil {
+		// A body no larger than the threshold can't have a file spill over it
+		r.ref.Body = http.MaxBytesReader(nil, body, maxMemory)
+	}
+	err := r.ref.ParseMultipartForm(maxMemory)
+	r.ref.Body = body
+	if err != nil {
+		r.overflow(err)
+	}
+	return err
+}
+
+/**
+ * @info Removes the temp files of the multipart form, which net/http only does for the request it created
+ */
+func (r *Request) cleanup() {
+	if r.ref != nil && r.ref.MultipartForm != nil {
+		r.ref.MultipartForm.RemoveAll()
+	}
+}
@@ -0,0 +1,256 @@
+package minima
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Hides the body length so the request reads like a chunked one
+func chunked(body string) io.Reader {
+	return io.MultiReader(strings.NewReader(body))
+}
+
+func TestBodyLimit(t *testing.T) {
+	big := `{"name":"` + strings.Repeat("a", 64) + `"}`
+	tests := []struct {
+		name    string
+		body    io.Reader
+		route   []func(http.Handler) http.Handler
+		handler Handler
+		status  int
+		reply   string
+	}{
+		{
+			name: "content length over the limit",
+			body: strings.NewReader(big),
+			handler: func(res *Response, req *Request) {
+				res.Send("handled")
+			},
+			status: 413,
+			reply:  "Payload too large",
+		},
+		{
+			name: "chunked body read by GetBody",
+			body: chunked(big),
+			handler: func(res *Response, req *Request) {
+				req.GetBody()
+				res.Send("handled")
+			},
+			status: 413,
+			reply:  "Payload too large",
+		},
+		{
+			name: "chunked body read by ParseBody",
+			body: chunked(big),
+			handler: func(res *Response, req *Request) {
+				if _, err := req.ParseBody(); err != nil {
+					res.Invalid(err)
+					return
+				}
+				res.Send("handled")
+			},
+			status: 413,
+			reply:  "Payload too large",
+		},
+		{
+			name: "route limit raises the global one",
+			body: chunked(big),
+			route: []func(http.Handler) http.Handler{
+				BodyLimit(1 << 10),
+			},
+			handler: func(res *Response, req *Request) {
+				res.Send(strings.Repeat("a", len(req.GetBody()["name"].(string))))
+			},
+			status: 200,
+			reply:  strings.Repeat("a", 64),
+		},
+		{
+			name: "body under the limit",
+			body: chunked(`{"name":"ada"}`),
+			handler: func(res *Response, req *Request) {
+				res.Send(req.GetBody()["name"].(string))
+			},
+			status: 200,
+			reply:  "ada",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := Engine().BodyLimit(32)
+			app.Post("/", tt.handler, tt.route...)
+			req := httptest.NewRequest("POST", "/", tt.body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != tt.status || w.Body.String() != tt.reply {
+				t.Errorf("got %d %q, want %d %q", w.Code, w.Body.String(), tt.status, tt.reply)
+			}
+		})
+	}
+}
+
+func TestMultipartSpill(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "big.bin")
+	fw.Write(bytes.Repeat([]byte("x"), 4<<10))
+	mw.Close()
+
+	for _, spill := range []bool{true, false} {
+		tmp := t.TempDir()
+		if spill {
+			t.Setenv("TMPDIR", tmp)
+		} else {
+			// Any attempt to write a temp file fails on a missing directory
+			t.Setenv("TMPDIR", tmp+"/missing")
+		}
+		var spilled int
+		var err error
+		app := Engine().MultipartMemory(1 << 10).MultipartSpill(spill)
+		app.Post("/", func(res *Response, req *Request) {
+			_, err = req.FormFile("file")
+			entries, _ := os.ReadDir(tmp)
+			spilled = len(entries)
+		})
+		req := httptest.NewRequest("POST", "/", bytes.NewReader(body.Bytes()))
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		app.ServeHTTP(httptest.NewRecorder(), req)
+
+		var tooLarge *http.MaxBytesError
+		if spill && (err != nil || spilled != 1) {
+			t.Errorf("spill: got %v with %d temp files, want the file on disk", err, spilled)
+		}
+		if !spill && (!errors.As(err, &tooLarge) || spilled != 0) {
+			t.Errorf("no spill: got %v with %d temp files, want a MaxBytesError and none", err, spilled)
+		}
+		if entries, _ := os.ReadDir(tmp); len(entries) != 0 {
+			t.Errorf("spill %v: %d temp files left after the request", spill, len(entries))
+		}
+	}
+}
+
+func TestMountedBodySettings(t *testing.T) {
+	read := func(res *Response, req *Request) {
+		if _, err := req.RawBody(); err == nil {
+			res.Send("read")
+		}
+	}
+	tests := []struct {
+		name   string
+		build  func() *Minima
+		host   string
+		path   string
+		status int
+	}{
+		{
+			name: "mounted router limit",
+			build: func() *Minima {
+				child := NewRouter().BodyLimit(4)
+				child.Post("/", read)
+				return Engine().Mount("/child", child)
+			},
+			path:   "/child",
+			status: 413,
+		},
+		{
+			name: "host router limit",
+			build: func() *Minima {
+				child := NewRouter().BodyLimit(4)
+				child.Post("/", read)
+				return Engine().Host("api.example.com", child)
+			},
+			host:   "api.example.com",
+			path:   "/",
+			status: 413,
+		},
+		{
+			name: "global limit applies to mounted routes",
+			build: func() *Minima {
+				child := NewRouter()
+				child.Post("/", read)
+				return Engine().BodyLimit(4).Mount("/child", child)
+			},
+			path:   "/child",
+			status: 413,
+		},
+		{
+			name: "mounted router lifts the global limit",
+			build: func() *Minima {
+				child := NewRouter().BodyLimit(0)
+				child.Post("/", read)
+				return Engine().BodyLimit(4).Mount("/child", child)
+			},
+			path:   "/child",
+			status: 200,
+		},
+		{
+			name: "nested router keeps its own limit",
+			build: func() *Minima {
+				inner := NewRouter().BodyLimit(4)
+				inner.Post("/", read)
+				outer := NewRouter().BodyLimit(1 << 10)
+				outer.Mount("/inner", inner)
+				return Engine().Mount("/outer", outer)
+			},
+			path:   "/outer/inner",
+			status: 413,
+		},
+		{
+			name: "nested router inherits the outer limit",
+			build: func() *Minima {
+				inner := NewRouter().MultipartMemory(1 << 10)
+				inner.Post("/", read)
+				outer := NewRouter().BodyLimit(4)
+				outer.Mount("/inner", inner)
+				return Engine().Mount("/outer", outer)
+			},
+			path:   "/outer/inner",
+			status: 413,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, chunked("0123456789"))
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			w := httptest.NewRecorder()
+			tt.build().ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("got %d %q, want %d", w.Code, w.Body.String(), tt.status)
+			}
+		})
+	}
+}
+
+func TestMountedMultipartSettings(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "big.bin")
+	fw.Write(bytes.Repeat([]byte("x"), 4<<10))
+	mw.Close()
+
+	var err error
+	child := NewRouter().MultipartMemory(1 << 10).MultipartSpill(false)
+	child.Post("/", func(res *Response, req *Request) {
+		_, err = req.FormFile("file")
+	})
+	app := Engine().Mount("/upload", child)
+	req := httptest.NewRequest("POST", "/upload", bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 1<<10 {
+		t.Errorf("got %v, want the mounted router's 1KiB cap", err)
+	}
+}
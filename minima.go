@@ -30,6 +30,7 @@ SOFTWARE.
 
 import (
 	"context"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -46,6 +47,7 @@ import (
  * @property {map[string]interface{}} [properties] The properties for the server instance
  * @property {*Config} [Config] The core config file for middlewares and router instances
  * @property {*time.Duration} [drain] The router's drain time
+ * @property {bool} [banner] Whether the route table is printed on Listen
  */
 type Minima struct {
 	server     *http.Server
@@ -54,10 +56,9 @@ type Minima struct {
 	router     *Router
 	properties map[string]interface{}
 	drain      time.Duration
+	banner     bool
 }
 
-
-
 /*
 *
 
@@ -98,9 +99,24 @@ func (m *Minima) Listen(addr string) error {
 	}
 	m.server = &http.Server{Addr: addr, Handler: m}
 	m.started = true
+	m.printBanner(os.Stdout, addr)
 	return m.server.ListenAndServe()
 }
 
+/**
+ * @info Prints the listening address and the route table when the banner is enabled
+ * @param {io.Writer} [w] The writer to print the route table to
+ * @param {string} [addr] The address the server listens on
+ * @returns {}
+ */
+func (m *Minima) printBanner(w io.Writer, addr string) {
+	if !m.banner {
+		return
+	}
+	log.Printf("Minima is listening on %s", addr)
+	m.router.PrintRoutes(w)
+}
+
 /**
  * @info Injects the actual minima server logic to http
  * @param {http.ResponseWriter} [w] The net/http response instance
@@ -108,37 +124,19 @@ func (m *Minima) Listen(addr string) error {
  * @returns {}
  */
 func (m *Minima) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	f, params := m.router.routes[r.Method].GetNode(r.URL.Path)
-
-	if f != nil {
-		handler := buildHandler(f.handler, params)
-		if err := r.ParseForm(); err != nil {
-			log.Printf("Error parsing form: %s", err)
-			return
-		}
-		
-		if m.router.handler != nil {
-			m.router.handler.ServeHTTP(w, r)
-		}
-		handler.ServeHTTP(w, r)
-	} else {
-		if m.router.notfound != nil {
-			m.router.notfound.ServeHTTP(w, r)
-		} else {
-			w.Write([]byte("No matching route found"))
-		}
-	}
+	m.router.ServeHTTP(w, r)
 }
 
 /**
  * @info Adds route with Get method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*minima}
  */
-func (m *Minima) Get(path string, handler Handler) *Minima {
+func (m *Minima) Get(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
 
-	m.router.Get(path, handler)
+	m.router.Get(path, handler, middlewares...)
 	return m
 }
 
@@ -146,10 +144,11 @@ func (m *Minima) Get(path string, handler Handler) *Minima {
  * @info Adds route with Put method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*minima}
  */
-func (m *Minima) Put(path string, handler Handler) *Minima {
-	m.router.Put(path, handler)
+func (m *Minima) Put(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
+	m.router.Put(path, handler, middlewares...)
 	return m
 }
 
@@ -157,10 +156,11 @@ func (m *Minima) Put(path string, handler Handler) *Minima {
  * @info Adds route with Options method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*minima}
  */
-func (m *Minima) Options(path string, handler Handler) *Minima {
-	m.router.Options(path, handler)
+func (m *Minima) Options(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
+	m.router.Options(path, handler, middlewares...)
 	return m
 }
 
@@ -168,10 +168,11 @@ func (m *Minima) Options(path string, handler Handler) *Minima {
  * @info Adds route with Head method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*minima}
  */
-func (m *Minima) Head(path string, handler Handler) *Minima {
-	m.router.Head(path, handler)
+func (m *Minima) Head(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
+	m.router.Head(path, handler, middlewares...)
 	return m
 }
 
@@ -179,10 +180,49 @@ func (m *Minima) Head(path string, handler Handler) *Minima {
  * @info Adds route with Delete method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*minima}
  */
-func (m *Minima) Delete(path string, handler Handler) *Minima {
-	m.router.Delete(path, handler)
+func (m *Minima) Delete(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
+	m.router.Delete(path, handler, middlewares...)
+	return m
+}
+
+/**
+ * @info Adds route with any method, including extension methods such as PROPFIND, MKCOL or QUERY
+ * @param {string} [method] The route method
+ * @param {string} [path] The route path
+ * @param {Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @returns {*minima}
+ */
+func (m *Minima) Handle(method string, path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
+	m.router.Handle(method, path, handler, middlewares...)
+	return m
+}
+
+/**
+ * @info Adds route with every standard method
+ * @param {string} [path] The route path
+ * @param {Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @returns {*minima}
+ */
+func (m *Minima) Any(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
+	m.router.Any(path, handler, middlewares...)
+	return m
+}
+
+/**
+ * @info Adds route with each of the given methods
+ * @param {[]string} [methods] The route methods
+ * @param {string} [path] The route path
+ * @param {Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @returns {*minima}
+ */
+func (m *Minima) Match(methods []string, path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
+	m.router.Match(methods, path, handler, middlewares...)
 	return m
 }
 
@@ -190,10 +230,11 @@ func (m *Minima) Delete(path string, handler Handler) *Minima {
  * @info Adds route with Patch method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*minima}
  */
-func (m *Minima) Patch(path string, handler Handler) *Minima {
-	m.router.Patch(path, handler)
+func (m *Minima) Patch(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
+	m.router.Patch(path, handler, middlewares...)
 	return m
 }
 
@@ -201,10 +242,11 @@ func (m *Minima) Patch(path string, handler Handler) *Minima {
  * @info Adds route with Post method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*minima}
  */
-func (m *Minima) Post(path string, handler Handler) *Minima {
-	m.router.Post(path, handler)
+func (m *Minima) Post(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Minima {
+	m.router.Post(path, handler, middlewares...)
 	return m
 }
 
@@ -218,6 +260,16 @@ func (m *Minima) NotFound(handler Handler) *Minima {
 	return m
 }
 
+/**
+ * @info Injects the MethodNotAllowed handler to the minima instance
+ * @param {Handler} [handler] Minima handler instance
+ * @returns {*minima}
+ */
+func (m *Minima) MethodNotAllowed(handler Handler) *Minima {
+	m.router.MethodNotAllowed(handler)
+	return m
+}
+
 /**
  * @info Injects the routes from the router to core stack
  * @param {*Router} [router] Minima router instance
@@ -228,6 +280,193 @@ func (m *Minima) UseRouter(router *Router) *Minima {
 	return m
 }
 
+/**
+ * @info Redirects paths missing only by a trailing slash, 301 for GET and 308 for other methods
+ * @param {bool} [enabled] Whether the redirect is enabled
+ * @returns {*minima}
+ */
+func (m *Minima) RedirectTrailingSlash(enabled bool) *Minima {
+	m.router.RedirectTrailingSlash(enabled)
+	return m
+}
+
+/**
+ * @info Redirects paths with extra slashes or dot segments to their cleaned form, 301 for GET and 308 for other methods
+ * @param {bool} [enabled] Whether the redirect is enabled
+ * @returns {*minima}
+ */
+func (m *Minima) RedirectFixedPath(enabled bool) *Minima {
+	m.router.RedirectFixedPath(enabled)
+	return m
+}
+
+/**
+ * @info Matches static path segments regardless of case
+ * @param {bool} [enabled] Whether case-insensitive matching is enabled
+ * @returns {*minima}
+ */
+func (m *Minima) CaseInsensitive(enabled bool) *Minima {
+	m.router.CaseInsensitive(enabled)
+	return m
+}
+
+/**
+ * @info Serves the routes of a router only for a host, e.g. api.example.com or :tenant.example.com capturing the tenant param
+ * @param {string} [pattern] The host pattern
+ * @param {*Router} [router] Minima router instance
+ * @returns {*minima}
+ */
+func (m *Minima) Host(pattern string, router *Router) *Minima {
+	m.router.Host(pattern, router)
+	return m
+}
+
+/**
+ * @info Removes a route, routes can be added and removed while the server is running
+ * @param {string} [method] The route method
+ * @param {string} [path] The route path
+ * @returns {error}
+ */
+func (m *Minima) Remove(method string, path string) error {
+	return m.router.Remove(method, path)
+}
+
+/**
+ * @info Creates a group whose routes are only served for an api version, picked by the API-Version header or an Accept vendor type such as application/vnd.acme.v2+json
+ * @param {string} [version] The api version, e.g. 2 or v2
+ * @returns {*Group}
+ */
+func (m *Minima) Version(version string) *Group {
+	return m.router.Version(version)
+}
+
+/**
+ * @info Sets the api version served to requests which don't ask for one
+ * @param {string} [version] The api version, e.g. 2 or v2
+ * @returns {*minima}
+ */
+func (m *Minima) DefaultVersion(version string) *Minima {
+	m.router.DefaultVersion(version)
+	return m
+}
+
+/**
+ * @info Caps the body of every request, answering 413 when the Content-Length is over the limit or a body accessor reads past it, use the BodyLimit middleware to set a route's own limit
+ * @param {int64} [limit] The maximum body size in bytes, zero or less for no limit
+ * @returns {*minima}
+ */
+func (m *Minima) BodyLimit(limit int64) *Minima {
+	m.router.BodyLimit(limit)
+	return m
+}
+
+/**
+ * @info Sets how much of a multipart form is kept in memory, larger files spill to os.TempDir() and are removed once the request ends
+ * @param {int64} [maxMemory] The in-memory threshold in bytes
+ * @returns {*minima}
+ */
+func (m *Minima) MultipartMemory(maxMemory int64) *Minima {
+	m.router.MultipartMemory(maxMemory)
+	return m
+}
+
+/**
+ * @info Sets whether multipart files over the in-memory threshold spill to temp files, when disabled multipart bodies over the threshold are rejected while reading so nothing is written to disk
+ * @param {bool} [enabled] Whether files spill to temp files
+ * @returns {*minima}
+ */
+func (m *Minima) MultipartSpill(enabled bool) *Minima {
+	m.router.MultipartSpill(enabled)
+	return m
+}
+
+/**
+ * @info Registers the decoder of a media type so GetBody, ParseBody and Bind understand bodies such as xml or csv
+ * @param {string} [mediaType] The media type without params, e.g. application/xml
+ * @param {Decoder} [decoder] The decoder
+ * @returns {*minima}
+ */
+func (m *Minima) RegisterDecoder(mediaType string, decoder Decoder) *Minima {
+	m.router.RegisterDecoder(mediaType, decoder)
+	return m
+}
+
+/**
+ * @info Makes registration errors such as conflicting routes panic instead of being logged
+ * @param {bool} [strict] Whether strict mode is enabled
+ * @returns {*minima}
+ */
+func (m *Minima) Strict(strict bool) *Minima {
+	m.router.Strict(strict)
+	return m
+}
+
+/**
+ * @info Returns the registration errors collected outside strict mode
+ * @returns {[]error}
+ */
+func (m *Minima) Errors() []error {
+	return m.router.Errors()
+}
+
+/**
+ * @info Names the last added route so urls can be generated for it
+ * @param {string} [name] The route name
+ * @returns {*minima}
+ */
+func (m *Minima) Name(name string) *Minima {
+	m.router.Name(name)
+	return m
+}
+
+/**
+ * @info Generates the url of a named route
+ * @param {string} [name] The route name
+ * @param {map[string]string} [params] The values for the route params
+ * @returns {string, error}
+ */
+func (m *Minima) URL(name string, params map[string]string) (string, error) {
+	return m.router.URL(name, params)
+}
+
+/**
+ * @info Mounts the routes from the router under a base path
+ * @param {string} [basePath] The path every route of the router is mounted under
+ * @param {*Router} [router] Minima router instance
+ * @returns {*minima}
+ */
+func (m *Minima) Mount(basePath string, router *Router) *Minima {
+	m.router.Mount(basePath, router)
+	return m
+}
+
+/**
+ * @info Lists every registered route
+ * @returns {[]RouteInfo}
+ */
+func (m *Minima) Routes() []RouteInfo {
+	return m.router.Routes()
+}
+
+/**
+ * @info Prints the route table
+ * @param {io.Writer} [w] The writer to print to
+ * @returns {error}
+ */
+func (m *Minima) PrintRoutes(w io.Writer) error {
+	return m.router.PrintRoutes(w)
+}
+
+/**
+ * @info Enables printing the route table when the server starts
+ * @param {bool} [enabled] Whether the banner is printed
+ * @returns {*minima}
+ */
+func (m *Minima) Banner(enabled bool) *Minima {
+	m.banner = enabled
+	return m
+}
+
 /**
  * @info The drain timeout for the core instance
  * @param {time.Duration} [time] The time period for drain
@@ -295,7 +534,10 @@ func (m *Minima) Use(handler Handler) *Minima {
  */
 func (m *Minima) UseGroup(grp *Group) *Minima {
 	for _, v := range grp.GetGroupRoutes() {
-		m.router.Register(v.method, v.path, v.handler)
+		m.router.report(m.router.add(v))
+	}
+	for _, err := range grp.takeErrors() {
+		m.router.report(err)
 	}
 	return m
 }
@@ -28,28 +28,59 @@ SOFTWARE.
 * Thank you for showing interest in minima and for this beautiful community
 */
 
+import "net/http"
+
+/**
+ * @info The kind of path segment an edge matches
+ */
+type edgeKind uint8
+
+const (
+	staticEdge edgeKind = iota
+	paramEdge
+	catchAllEdge
+)
+
 /**
  * @info The tree edge structure
  * @property {string} [key] The key of the edge
+ * @property {edgeKind} [kind] Whether the edge is static, a param or a catch-all
+ * @property {string} [name] The param name captured by dynamic edges
+ * @property {constraint} [match] The constraint a param edge has to satisfy
  * @property {Node} [n] The tree node
  */
 type edge struct {
-	key string
-	n   *Node
+	key   string
+	kind  edgeKind
+	name  string
+	match constraint
+	n     *Node
 }
 
 /**
  * @info The tree Node structure
  * @property {Handler} [handler] The handler to be used
+ * @property {[]func(http.Handler)http.Handler} [middlewares] The middlewares wrapping the handler
+ * @property {http.Handler} [chained] The handler chained with its middlewares, nil without middlewares
+ * @property {map[string]*Node} [versions] The handlers registered for an api version on the same path
+ * @property {*bodySettings} [body] The body settings of the router the route was mounted from
+ * @property {*Group} [group] The router bound group the route was added by
+ * @property {http.Handler} [live] The handler wrapped with the group middlewares current when serving
  * @property {[]*edge} [edges] The array of node edges
  * @property {int} [priority] The priority of the node in the tree
  * @property {int} [depth] The depth of the node in the tree
  */
 type Node struct {
-	handler  Handler
-	edges    []*edge
-	priority int
-	depth    int
+	handler     Handler
+	middlewares []func(http.Handler) http.Handler
+	chained     http.Handler
+	versions    map[string]*Node
+	body        *bodySettings
+	group       *Group
+	live        http.Handler
+	edges       []*edge
+	priority    int
+	depth       int
 }
 
 /**
@@ -64,6 +95,27 @@ func (n *Node) IsLeaf() bool {
 	return length == 0
 }
 
+/**
+ * @info Whether a route ends at the node, with or without a version
+ * @returns {bool}
+ */
+func (n *Node) routable() bool {
+	return n.handler != nil || len(n.versions) > 0
+}
+
+/**
+ * @info The handler chaining the route with its own and group middlewares, nil when none wrap it
+ * @returns {http.Handler}
+ */
+func (n *Node) chain() http.Handler {
+	if n.group != nil {
+		if stack := n.group.stack.Load(); stack != nil && len(*stack) > 0 {
+			return n.live
+		}
+	}
+	return n.chained
+}
+
 /**
  * @info Increases node's depth in the tree
  */
@@ -74,6 +126,46 @@ func (n *Node) incrDepth() {
 	}
 }
 
+/**
+ * @info Copies the node and its edge list so it can be changed without touching the published tree
+ * @returns {*Node}
+ */
+func (n *Node) fork() *Node {
+	c := *n
+	c.edges = append(make([]*edge, 0, len(n.edges)+1), n.edges...)
+	return &c
+}
+
+/**
+ * @info The matching rank of an edge, lower ranks are tried first
+ * @returns {int}
+ */
+func (e *edge) rank() int {
+	switch {
+	case e.kind == staticEdge:
+		return 0
+	case e.kind == paramEdge && e.match != nil:
+		return 1
+	case e.kind == paramEdge:
+		return 2
+	}
+	return 3
+}
+
+/**
+ * @info Adds an edge after the edges of the same or a lower rank
+ * @param {*edge} [e] The edge to add
+ */
+func (n *Node) addEdge(e *edge) {
+	i := len(n.edges)
+	for i > 0 && n.edges[i-1].rank() > e.rank() {
+		i--
+	}
+	n.edges = append(n.edges, nil)
+	copy(n.edges[i+1:], n.edges[i:])
+	n.edges[i] = e
+}
+
 /**
  * @info Clones the current node
  * @returns {*Node}
@@ -83,3 +175,17 @@ func (n *Node) clone() *Node {
 	c.incrDepth()
 	return &c
 }
+
+/**
+ * @info Walks the node and its children, calling fn for every node holding a handler
+ * @param {string} [prefix] The path leading to the node
+ * @param {func(string, *Node)} [fn] The callback receiving the full path and the node
+ */
+func (n *Node) walk(prefix string, fn func(key string, n *Node)) {
+	if n.routable() {
+		fn(prefix, n)
+	}
+	for _, e := range n.edges {
+		e.n.walk(prefix+e.key, fn)
+	}
+}
@@ -3,7 +3,9 @@ package minima
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"net/url"
 )
 
 func ParseRequestBody(r *http.Request) (map[string]interface{}, error) {
@@ -12,34 +14,36 @@ func ParseRequestBody(r *http.Request) (map[string]interface{}, error) {
 	}
 	defer r.Body.Close()
 
-	switch r.Header.Get("Content-Type") {
-	case "application/x-www-form-urlencoded":
-		return parseFormData(r)
-	case "application/json":
-		return parseJSONData(r)
-	default:
+	decode := findDecoder(mediaType(r.Header.Get("Content-Type")))
+	if decode == nil {
 		return nil, nil // Ignore other content types
 	}
+	return decode(r.Body)
 }
 
-func parseFormData(r *http.Request) (map[string]interface{}, error) {
-	if err := r.ParseForm(); err != nil {
+func parseFormData(body io.Reader) (map[string]interface{}, error) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	form, err := url.ParseQuery(string(raw))
+	if err != nil {
 		return nil, err
 	}
 
-	data := make(map[string]interface{}, len(r.Form))
-	for k, v := range r.Form {
+	data := make(map[string]interface{}, len(form))
+	for k, v := range form {
 		data[k] = v
 	}
 
 	return data, nil
 }
 
-func parseJSONData(r *http.Request) (map[string]interface{}, error) {
+func parseJSONData(body io.Reader) (map[string]interface{}, error) {
 	var data map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+	if err := json.NewDecoder(body).Decode(&data); err != nil {
 		return nil, err
 	}
 
 	return data, nil
-}
\ No newline at end of file
+}
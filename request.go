@@ -29,7 +29,10 @@ SOFTWARE.
 */
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"mime/multipart"
 	"net"
 	"net/http"
@@ -41,48 +44,177 @@ import (
  * @info The request structure
  * @property {*http.Request} [ref] The net/http request instance
  * @property {multipart.Reader} [fileReader] file reader instance
- * @property {map[string][]string} [body] Value of the request body
+ * @property {map[string]interface{}} [body] Value of the request body, parsed on first use
+ * @property {error} [bodyErr] The error parsing the request body
+ * @property {bool} [parsed] Whether the request body was parsed
+ * @property {[]byte} [raw] The buffered raw request body
+ * @property {bool} [buffered] Whether the raw request body was buffered
+ * @property {int64} [maxMemory] The in-memory threshold of multipart forms
+ * @property {bool} [noSpill] Whether multipart files over the threshold fail instead of spilling to temp files
+ * @property {map[string]Decoder} [decoders] The body decoders registered on the router
+ * @property {map[string]Decoder} [routeDecoders] The body decoders of the router the route was mounted from, tried first
  * @property {string} [method] Request method
- * @property {[]*Params} [Params] Request path parameters
+ * @property {Params} [Params] Request path parameters
  * @property {query} [url.Values] Request path query params
  * @property {json.Decoder} [json] Json decoder instance
+ * @property {map[string]interface{}} [locals] Request scoped values shared from middlewares to handlers
+ * @property {func()} [next] Runs the rest of the middleware chain
  */
 type Request struct {
-	ref        *http.Request
-	fileReader *multipart.Reader
-	method     string
-	Params     map[string]string
-	body       map[string]interface{}
-	json       *json.Decoder
+	ref           *http.Request
+	fileReader    *multipart.Reader
+	method        string
+	Params        Params
+	body          map[string]interface{}
+	bodyErr       error
+	parsed        bool
+	raw           []byte
+	buffered      bool
+	maxMemory     int64
+	noSpill       bool
+	decoders      map[string]Decoder
+	routeDecoders map[string]Decoder
+	json          *json.Decoder
+	locals        map[string]interface{}
+	next          func()
 }
 
 /**
- * @info Make a new default request instance
+ * @info A single route path parameter
+ * @property {string} [Key] The param name
+ * @property {string} [Value] The captured value
+ */
+type Param struct {
+	Key   string
+	Value string
+}
+
+/**
+ * @info The route path parameters, kept in a slice so capturing them doesn't allocate
+ */
+type Params []Param
+
+/**
+ * @info Gets the value of a param
+ * @param {string} [key] Key of the param
+ * @returns {string}
+ */
+func (p Params) Get(key string) string {
+	for i := range p {
+		if p[i].Key == key {
+			return p[i].Value
+		}
+	}
+	return ""
+}
+
+/**
+ * @info Sets the value of a param, replacing an existing one with the same key
+ * @param {string} [key] Key of the param
+ * @param {string} [value] Value of the param
+ */
+func (p *Params) Set(key string, value string) {
+	for i := range *p {
+		if (*p)[i].Key == key {
+			(*p)[i].Value = value
+			return
+		}
+	}
+	*p = append(*p, Param{Key: key, Value: value})
+}
+
+/**
+ * @info The context key under which the request instance is shared along the chain
+ */
+type requestKey struct{}
+
+/**
+ * @info Make a new default request instance, reusing the one shared by earlier middlewares
  * @param {http.Request} [http.Request] The net/http request instance
  * @returns {Request}
  */
 func request(r *http.Request) *Request {
-	body, err := ParseRequestBody(r); if err != nil {
-		panic(err)
-	}
-	req := &Request{
-		ref:        r,
-		fileReader: nil,
-		method:     r.Proto,
-		Params:     make(map[string]string),
-		body:       make(map[string]interface{}),
+	if req, ok := r.Context().Value(requestKey{}).(*Request); ok {
+		req.ref = r
+		return req
 	}
-	req.body = body
+	req := &Request{}
+	req.reset(r)
 	return req
 }
 
+/**
+ * @info Resets a request instance for a new net/http request, keeping its params
+ * @param {http.Request} [r] The net/http request instance
+ * @returns {}
+ */
+func (r *Request) reset(rq *http.Request) {
+	*r = Request{
+		ref:    rq,
+		method: rq.Method,
+		Params: r.Params,
+	}
+}
+
+/**
+ * @info Stores the request in its context so the rest of the chain reuses the same instance
+ * @returns {*Request}
+ */
+func (r *Request) share() *Request {
+	if req, _ := r.ref.Context().Value(requestKey{}).(*Request); req != r {
+		r.ref = r.ref.WithContext(context.WithValue(r.ref.Context(), requestKey{}, r))
+	}
+	return r
+}
+
+/**
+ * @info Runs the rest of the middleware chain, letting middlewares wrap the route handler
+ * @returns {}
+ */
+func (r *Request) Next() {
+	if r.next != nil {
+		r.next()
+	}
+}
+
+/**
+ * @info Sets a request scoped value readable by later middlewares and the route handler
+ * @param {string} [key] Key of the value
+ * @param {interface{}} [value] The value to store
+ * @returns {*Request}
+ */
+func (r *Request) Set(key string, value interface{}) *Request {
+	if r.locals == nil {
+		r.locals = make(map[string]interface{})
+	}
+	r.locals[key] = value
+	return r
+}
+
+/**
+ * @info Gets a request scoped value
+ * @param {string} [key] Key of the value
+ * @returns {interface{}}
+ */
+func (r *Request) Get(key string) interface{} {
+	return r.locals[key]
+}
+
+/**
+ * @info Gets all the request scoped values
+ * @returns {map[string]interface{}}
+ */
+func (r *Request) Locals() map[string]interface{} {
+	return r.locals
+}
+
 /**
  * @info Gets param from route path
  * @param {string} [key] Key of the route param
  * @returns {string}
  */
 func (r *Request) Param(key string) string {
-	return r.Params[key]
+	return r.Params.Get(key)
 }
 
 /**
@@ -92,7 +224,7 @@ func (r *Request) Param(key string) string {
  * @returns {Respone}
  */
 func (r *Request) SetParam(key string, value string) *Request {
-	r.Params[key] = value
+	r.Params.Set(key, value)
 	return r
 }
 
@@ -105,31 +237,87 @@ func (r *Request) Path() string {
 }
 
 /**
- * @info Gets raw request body
- * @returns {map[string][]string}
+ * @info Reads the whole request body once, buffering it so it can be read again by ParseBody, BodyReader and the handler
+ * @returns {[]byte, error}
  */
+func (r *Request) RawBody() ([]byte, error) {
+	if r.buffered || r.ref.Body == nil || r.ref.Body == http.NoBody {
+		return r.raw, nil
+	}
+	raw, err := io.ReadAll(r.ref.Body)
+	r.ref.Body.Close()
+	if err != nil {
+		r.overflow(err)
+		return nil, err
+	}
+	r.raw, r.buffered = raw, true
+	r.ref.Body = io.NopCloser(bytes.NewReader(raw))
+	return raw, nil
+}
 
+/**
+ * @info Gets the request body stream, a fresh reader over the buffer once the body was buffered
+ * @returns {io.ReadCloser}
+ */
+func (r *Request) BodyReader() io.ReadCloser {
+	if r.buffered {
+		return io.NopCloser(bytes.NewReader(r.raw))
+	}
+	if r.ref.Body == nil {
+		return http.NoBody
+	}
+	return r.ref.Body
+}
+
+/**
+ * @info Parses the request body on first use with the decoder of its media type, bodies without a decoder are left unread
+ * @returns {map[string]interface{}, error}
+ */
+func (r *Request) ParseBody() (map[string]interface{}, error) {
+	if r.parsed {
+		return r.body, r.bodyErr
+	}
+	r.parsed = true
+	decode := findDecoder(mediaType(r.ref.Header.Get("Content-Type")), r.routeDecoders, r.decoders)
+	if decode == nil {
+		return nil, nil
+	}
+	raw, err := r.RawBody()
+	if err != nil || len(raw) == 0 {
+		r.bodyErr = err
+		return nil, err
+	}
+	r.body, r.bodyErr = decode(bytes.NewReader(raw))
+	return r.body, r.bodyErr
+}
 
-// GetParsedBody retrieves the parsed request body from the context.
-func (r *Request) GetBody() (map[string]interface{}) {
-	return r.body 
+/**
+ * @info Gets the parsed request body, nil when it can't be parsed, see ParseBody for the error. A body over the body limit is answered with 413
+ * @returns {map[string]interface{}}
+ */
+func (r *Request) GetBody() map[string]interface{} {
+	body, _ := r.ParseBody()
+	return body
 }
 
 /**
  * @info Gets specified request body
  * @param {string} [key] Key of the request body
- * @returns {[]string}
+ * @returns {interface{}, bool}
  */
-func (r *Request) GetBodyValue(key string)(interface{}, bool) {
-	value, ok := r.body[key]
+func (r *Request) GetBodyValue(key string) (interface{}, bool) {
+	value, ok := r.GetBody()[key]
 	return value, ok
 }
 
 /**
- * @info Gets raw json decoder instance
+ * @info Gets a json decoder reading the request body
  * @returns {json.Decoder}
  */
 func (r *Request) Json() *json.Decoder {
+	if r.json == nil {
+		r.json = json.NewDecoder(r.BodyReader())
+	}
 	return r.json
 }
 
@@ -237,6 +425,11 @@ func (r *Request) SchemeType() string {
  * @returns {string}
  */
 func (r *Request) FormValue(key string) string {
+	if isMultipart(r.ref) {
+		r.parseMultipart()
+	} else {
+		r.parseForm()
+	}
 	return r.ref.FormValue(key)
 }
 
@@ -245,23 +438,44 @@ func (r *Request) FormValue(key string) string {
  * @returns {url.Values, error}
  */
 func (r *Request) FormParams() (url.Values, error) {
-	if strings.HasPrefix(r.ref.Header.Get("Content-type"), "multipart/form-data") {
-		if err := r.ref.ParseMultipartForm(24); err != nil {
-			return nil, err
-		}
-	} else {
-		if err := r.ref.ParseForm(); err != nil {
+	if isMultipart(r.ref) {
+		if err := r.parseMultipart(); err != nil {
 			return nil, err
 		}
+	} else if err := r.parseForm(); err != nil {
+		return nil, err
 	}
 	return r.ref.Form, nil
 }
 
+/**
+ * @info Parses the query and urlencoded body into the form, buffering the body first so ParseBody and BodyReader can still read it
+ * @returns {error}
+ */
+func (r *Request) parseForm() error {
+	if r.ref.PostForm != nil {
+		return nil
+	}
+	if mediaType(r.ref.Header.Get("Content-Type")) == "application/x-www-form-urlencoded" {
+		if _, err := r.RawBody(); err != nil {
+			return err
+		}
+	}
+	err := r.ref.ParseForm()
+	if r.buffered {
+		r.ref.Body = io.NopCloser(bytes.NewReader(r.raw))
+	}
+	return err
+}
+
 /**
  * @info Gets a file from request form
  * @returns {multipart.FileHeader, error}
  */
 func (r *Request) FormFile(key string) (*multipart.FileHeader, error) {
+	if err := r.parseMultipart(); err != nil {
+		return nil, err
+	}
 	f, file, err := r.ref.FormFile(key)
 	if err != nil {
 		return nil, err
@@ -275,7 +489,7 @@ func (r *Request) FormFile(key string) (*multipart.FileHeader, error) {
  * @returns {multipart.Form, error}
  */
 func (r *Request) MultipartForm() (*multipart.Form, error) {
-	err := r.ref.ParseMultipartForm(24)
+	err := r.parseMultipart()
 	return r.ref.MultipartForm, err
 }
 
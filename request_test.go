@@ -0,0 +1,50 @@
+package minima
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFormAndBodyInAnyOrder(t *testing.T) {
+	reads := map[string]func(req *Request) string{
+		"form then body": func(req *Request) string {
+			return req.FormValue("name") + "," + fmt.Sprint(req.GetBody()["name"])
+		},
+		"body then form": func(req *Request) string {
+			body := fmt.Sprint(req.GetBody()["name"])
+			return req.FormValue("name") + "," + body
+		},
+		"params then raw": func(req *Request) string {
+			form, err := req.FormParams()
+			if err != nil {
+				return err.Error()
+			}
+			raw, _ := io.ReadAll(req.BodyReader())
+			return form.Get("name") + "," + string(raw)
+		},
+	}
+	want := map[string]string{
+		"form then body":  "ada,[ada]",
+		"body then form":  "ada,[ada]",
+		"params then raw": "ada,name=ada",
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			app := Engine()
+			app.Post("/", func(res *Response, req *Request) {
+				res.Send(read(req))
+			})
+			req := httptest.NewRequest("POST", "/", strings.NewReader("name=ada"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Body.String() != want[name] {
+				t.Errorf("got %q, want %q", w.Body.String(), want[name])
+			}
+		})
+	}
+}
@@ -65,6 +65,7 @@ var statusCodes = map[string]int{
 	"Method Not Allowed":         405,
 	"Payload Too Large":          413,
 	"URI Too Long":               414,
+	"Unprocessable Entity":       422,
 	"Internal Server Error":      500,
 	"Not Implemented":            501,
 	"Bad Gateway":                502,
@@ -32,6 +32,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"io"
 	"log"
 	"net/http"
@@ -48,6 +49,8 @@ import (
  * @property {OutgoingHeader} [header] The response header instance
  * @property {string} [host] The minima host
  * @property {bool} [HasEnded] Whether the response has ended
+ * @property {bool} [aborted] Whether the middleware chain was stopped
+ * @property {OutgoingHeader} [outgoing] The storage behind header, so a response is a single allocation
  */
 type Response struct {
 	ref      http.ResponseWriter
@@ -57,6 +60,25 @@ type Response struct {
 	header   *OutgoingHeader
 	host     string
 	HasEnded bool
+	aborted  bool
+	outgoing OutgoingHeader
+}
+
+/**
+ * @info A response writer that drops the body, used to answer HEAD with GET handlers
+ * @property {http.ResponseWriter} [ResponseWriter] The wrapped net/http response instance
+ */
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+/**
+ * @info Discards the body while reporting it as written
+ * @param {[]byte} [b] The body bytes
+ * @returns {int, error}
+ */
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
 }
 
 /**
@@ -66,13 +88,26 @@ type Response struct {
  * @returns {Response}
  */
 func response(rw http.ResponseWriter, req *http.Request) *Response {
-	return &Response{
-		ref:    rw,
-		header: NewResHeader(rw, req),
-		url:    req.URL.Path,
-		method: req.Method,
-		host:   req.Host,
+	res := &Response{}
+	res.reset(rw, req)
+	return res
+}
+
+/**
+ * @info Resets a response instance for a new request
+ * @param {http.ResponseWriter} [rw] The net/http response instance
+ * @param {http.Request} [req] The net/http request instance
+ * @returns {}
+ */
+func (res *Response) reset(rw http.ResponseWriter, req *http.Request) {
+	*res = Response{
+		ref:      rw,
+		url:      req.URL.Path,
+		method:   req.Method,
+		host:     req.Host,
+		outgoing: OutgoingHeader{req, rw},
 	}
+	res.header = &res.outgoing
 }
 
 /**
@@ -322,6 +357,15 @@ func (res *Response) File(dir string) error {
 	return nil
 }
 
+/**
+ * @info Stops the middleware chain so later middlewares and the route handler don't run
+ * @returns {Response}
+ */
+func (res *Response) Abort() *Response {
+	res.aborted = true
+	return res
+}
+
 /**
  * @info Redirects to a different route
  * @param {string} [url] The url of the route to redirect
@@ -427,6 +471,59 @@ func (res *Response) NotFound() *Response {
 	return res
 }
 
+/**
+ * @info Set status code as 413
+ * @returns {Response}
+ */
+func (res *Response) PayloadTooLarge() *Response {
+	res.Status(statusCodes["Payload Too Large"])
+	return res
+}
+
+/**
+ * @info Set status code as 422
+ * @returns {Response}
+ */
+func (res *Response) UnprocessableEntity() *Response {
+	res.Status(statusCodes["Unprocessable Entity"])
+	return res
+}
+
+/**
+ * @info Writes a bind or validation error as json, a 422 with the field errors, e.g. {"errors":[{"field":"name","rule":"min","param":"3","message":"must be at least 3 characters"}]}, a 400 for a malformed body, a 413 for a body over the body limit and a logged 500 for anything else such as an unknown validation rule
+ * @param {error} [err] The error returned by Bind, Validate, BindAndValidate or ParseBody
+ * @returns {Response}
+ */
+func (res *Response) Invalid(err error) *Response {
+	var body struct {
+		Errors interface{} `json:"errors"`
+	}
+	var fields FieldErrors
+	var violations Violations
+	var tooLarge *http.MaxBytesError
+	var bodyErr *BodyError
+	// Status flushes the headers, so the content type goes first
+	res.setContent("application/json")
+	switch {
+	case errors.As(err, &tooLarge):
+		body.Errors = []FieldError{{Message: tooLarge.Error()}}
+		return res.PayloadTooLarge().JSON(body)
+	case errors.As(err, &violations):
+		body.Errors = violations
+	case errors.As(err, &fields):
+		body.Errors = fields
+	case errors.As(err, &bodyErr):
+		body.Errors = []FieldError{{Message: bodyErr.Error()}}
+		return res.BadRequest().JSON(body)
+	default:
+		// Errors such as an unknown validation rule are the server's fault and stay out of the response
+		log.Printf("Minima: %v", err)
+		body.Errors = []FieldError{{Message: "Internal Server Error"}}
+		return res.InternalServerError().JSON(body)
+	}
+	return res.UnprocessableEntity().JSON(body)
+}
+
 /**
  * @info Set status code as 500
  * @returns {Response}
@@ -30,7 +30,14 @@ SOFTWARE.
 
 import (
 	"fmt"
+	"log"
 	"net/http"
+	neturl "net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 type Handler func(res *Response, req *Request)
@@ -40,30 +47,96 @@ type Handler func(res *Response, req *Request)
  * @property {string} [method] The route method
  * @property {Handler} [handler] The handler for the cached route
  * @property {string} [path] The path of the cached route
+ * @property {[]func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @property {string} [name] The name used to generate urls for the route
+ * @property {string} [version] The api version the route is served for, empty for every version
+ * @property {*bodySettings} [body] The body settings of the router the route was mounted from, nil to use the serving router's
+ * @property {*Group} [group] The router bound group the route was added by, whose middlewares are resolved when serving
  */
 type cacheRoute struct {
-	method  string
-	path    string
-	handler Handler
+	method      string
+	path        string
+	handler     Handler
+	middlewares []func(http.Handler) http.Handler
+	name        string
+	version     string
+	body        *bodySettings
+	group       *Group
+}
+
+/**
+ * @info Copies the cached route with extra middlewares running before its own
+ * @param {[]func(http.Handler)http.Handler} [middlewares] The middlewares to prepend
+ * @returns {*cacheRoute}
+ */
+func (c *cacheRoute) wrap(middlewares []func(http.Handler) http.Handler) *cacheRoute {
+	if len(middlewares) == 0 {
+		return c
+	}
+	w := *c
+	w.middlewares = make([]func(http.Handler) http.Handler, 0, len(middlewares)+len(c.middlewares))
+	w.middlewares = append(append(w.middlewares, middlewares...), c.middlewares...)
+	return &w
+}
+
+/**
+ * @info A router mounted under a base path
+ * @property {string} [prefix] The base path of the mounted router
+ * @property {http.Handler} [notfound] The NotFound handler of the mounted router
+ */
+type mount struct {
+	prefix   string
+	notfound http.Handler
 }
 
 /**
  * @info The router structure
- * @property {map[string][]*tree} [routes] The radix-tree based routes
+ * @property {atomic.Pointer[map[string]*tree]} [routes] The radix-tree based routes per method, replaced whole when a method is added
  * @property {Handler} [notfound] The handler for the non matching routes
+ * @property {Handler} [methodnotallowed] The handler for routes matching only under other methods
  * @property {[]Handler} [minmiddleware] The minima handler middleware stack
  * @property {[]func(http.Handler)http.Handler} [middleware] The http.Handler middleware stack
  * @property {bool} [isCache] Whether the router is cache or not
  * @property {[]*cacheRoute} [cacheRoute] Slice of cached routes
- * @property {http.Handler} [handler] The single http.Handler built on chaining the whole middleware stack
+ * @property {[]*Group} [groups] The groups created on the router
+ * @property {atomic.Pointer[[]*mount]} [mounts] The NotFound handlers of routers mounted under a base path, replaced whole when a router is mounted
+ * @property {map[string]*cacheRoute} [names] The named routes
+ * @property {*cacheRoute} [last] The last added route, named by Name
+ * @property {bool} [strict] Whether registration errors panic
+ * @property {[]error} [errors] The registration errors collected outside strict mode
+ * @property {bool} [redirectTrailingSlash] Whether missed paths redirect to their toggled trailing slash form
+ * @property {bool} [redirectFixedPath] Whether missed paths redirect to their cleaned form
+ * @property {bool} [caseInsensitive] Whether static segments match regardless of case
+ * @property {bodySettings} [body] The body limit, multipart and decoder settings
+ * @property {string} [defaultVersion] The api version served to requests which don't ask for one
+ * @property {atomic.Pointer[[]*host]} [hosts] The routers selected by the request host before the path lookup, replaced whole when a host is added
+ * @property {sync.Mutex} [mu] The mutex guarding the route records so routes can be added and removed while serving
+ * @property {atomic.Pointer[http.Handler]} [handler] The single http.Handler chaining the whole middleware stack into the router
+ * @property {bool} [sealed] Whether a route was added, after which the middleware stack can't change
  */
 type Router struct {
-	notfound    http.Handler
-	handler     http.Handler
-	isCache     bool
-	middlewares []func(http.Handler) http.Handler
-	cacheRoute  []*cacheRoute
-	routes      map[string]*tree
+	notfound         http.Handler
+	methodnotallowed http.Handler
+	handler          atomic.Pointer[http.Handler]
+	sealed           bool
+	isCache          bool
+	middlewares      []func(http.Handler) http.Handler
+	cacheRoute       []*cacheRoute
+	groups           []*Group
+	mounts           atomic.Pointer[[]*mount]
+	names            map[string]*cacheRoute
+	last             *cacheRoute
+	strict           bool
+	errors           []error
+	routes           atomic.Pointer[map[string]*tree]
+
+	redirectTrailingSlash bool
+	redirectFixedPath     bool
+	caseInsensitive       bool
+	defaultVersion        string
+	body                  bodySettings
+	hosts                 atomic.Pointer[[]*host]
+	mu                    sync.Mutex
 }
 
 /*
@@ -74,64 +147,460 @@ return {Router}
 */
 func NewRouter() *Router {
 	r := &Router{
-		routes: map[string]*tree{
-			"GET":     NewTree(),
-			"POST":    NewTree(),
-			"PUT":     NewTree(),
-			"DELETE":  NewTree(),
-			"PATCH":   NewTree(),
-			"OPTIONS": NewTree(),
-			"HEAD":    NewTree(),
-		},
-		isCache:    true,
-		notfound:   nil,
+		isCache:     true,
+		notfound:    nil,
 		middlewares: make([]func(http.Handler) http.Handler, 0),
-		cacheRoute: make([]*cacheRoute, 0),
+		cacheRoute:  make([]*cacheRoute, 0),
+		names:       make(map[string]*cacheRoute),
 	}
+	routes := make(map[string]*tree, len(methods))
+	for _, method := range methods {
+		routes[method] = NewTree()
+	}
+	r.routes.Store(&routes)
+	r.buildHandler()
 	return r
 }
 
+/**
+ * @info The methods every router has a tree for, also registered by Any
+ */
+var methods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"}
+
+/**
+ * @info Returns the method trees of the router
+ * @returns {map[string]*tree}
+ */
+func (r *Router) trees() map[string]*tree {
+	return *r.routes.Load()
+}
+
+/**
+ * @info Returns the tree of a method, creating it for extension methods such as PROPFIND
+ * @param {string} [method] The route method
+ * @returns {*tree, error}
+ */
+func (r *Router) tree(method string) (*tree, error) {
+	if routes, ok := r.trees()[method]; ok {
+		return routes, nil
+	}
+	if !validMethod(method) {
+		return nil, fmt.Errorf("method %q not valid", method)
+	}
+	// Lookups keep reading the old map until the copy holding the new tree is swapped in
+	old := r.trees()
+	routes := make(map[string]*tree, len(old)+1)
+	for k, v := range old {
+		routes[k] = v
+	}
+	tr := NewTree()
+	tr.fold = r.caseInsensitive
+	routes[method] = tr
+	r.routes.Store(&routes)
+	return tr, nil
+}
+
+/**
+ * @info Checks that a method is a non empty http token
+ * @param {string} [method] The method to check
+ * @returns {bool}
+ */
+func validMethod(method string) bool {
+	if method == "" {
+		return false
+	}
+	for i := 0; i < len(method); i++ {
+		if c := method[i]; c <= ' ' || c >= 0x7f || strings.IndexByte("\"(),/:;<=>?@[\\]{}", c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
 /*
 *
   - @info Registers a new route to router interface
   - @param {string} [path] The route path
+  - @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
 
-return {string, []string}
+return {error}
 */
-func (r *Router) Register(method string, path string, handler Handler) error {
-	if r.handler == nil {
-		r.buildHandler()
-	}
-	if r.isCache {
-		r.cacheRoute = append(r.cacheRoute, &cacheRoute{
-			method:  method,
-			path:    path,
-			handler: handler,
-		})
-		return nil
+func (r *Router) Register(method string, path string, handler Handler, middlewares ...func(http.Handler) http.Handler) error {
+	return r.add(&cacheRoute{
+		method:      method,
+		path:        path,
+		handler:     handler,
+		middlewares: middlewares,
+	})
+}
+
+/**
+ * @info Adds a route record to the cache or straight into the method trees
+ * @param {*cacheRoute} [route] The route to add
+ * @returns {error}
+ */
+func (r *Router) add(route *cacheRoute) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last = nil
+	r.sealed = true
+	if !r.isCache {
+		routes, err := r.tree(route.method)
+		if err != nil {
+			return err
+		}
+		if err = routes.insertRoute(route); err != nil {
+			return fmt.Errorf("%s %w", route.method, err)
+		}
+	} else {
+		for _, v := range r.cacheRoute {
+			if v.method == route.method && v.path == route.path && v.version == route.version {
+				return fmt.Errorf("%s route %s is already registered", route.method, route.path)
+			}
+		}
 	}
-	routes, ok := r.routes[method]
-	if !ok {
-		return fmt.Errorf("method %s not valid", method)
+	r.cacheRoute = append(r.cacheRoute, route)
+	r.last = route
+	if route.name != "" {
+		if other, ok := r.names[route.name]; ok {
+			return fmt.Errorf("name %s of %s route %s is already used by %s route %s", route.name, route.method, route.path, other.method, other.path)
+		}
+		r.names[route.name] = route
 	}
+	return nil
+}
 
-	routes.InsertNode(path, handler)
+/**
+ * @info Removes a route with all its versions, safe to call while serving as in-flight requests keep the tree they started with
+ * @param {string} [method] The route method
+ * @param {string} [path] The route path
+ * @returns {error}
+ */
+func (r *Router) Remove(method string, path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	routes := make([]*cacheRoute, 0, len(r.cacheRoute))
+	for _, v := range r.cacheRoute {
+		if v.method != method || v.path != path {
+			routes = append(routes, v)
+		}
+	}
+	if !r.isCache {
+		tr, ok := r.trees()[method]
+		if !ok {
+			return fmt.Errorf("%s route %s is not registered", method, path)
+		}
+		if err := tr.RemoveNode(path); err != nil {
+			return fmt.Errorf("%s %w", method, err)
+		}
+	} else if len(routes) == len(r.cacheRoute) {
+		return fmt.Errorf("%s route %s is not registered", method, path)
+	}
+	for _, v := range r.cacheRoute {
+		if v.method != method || v.path != path {
+			continue
+		}
+		if v.name != "" && r.names[v.name] == v {
+			delete(r.names, v.name)
+		}
+		if r.last == v {
+			r.last = nil
+		}
+	}
+	r.cacheRoute = routes
 	return nil
 }
 
+/**
+ * @info Makes registration errors such as conflicting routes panic instead of being logged
+ * @param {bool} [strict] Whether strict mode is enabled
+ * @returns {*Router}
+ */
+func (r *Router) Strict(strict bool) *Router {
+	r.strict = strict
+	return r
+}
+
+/**
+ * @info Returns the registration errors collected outside strict mode
+ * @returns {[]error}
+ */
+func (r *Router) Errors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.errors...)
+}
+
+/**
+ * @info Surfaces a registration error, panicking in strict mode
+ * @param {error} [err] The registration error
+ */
+func (r *Router) report(err error) {
+	if err == nil {
+		return
+	}
+	if r.strict {
+		panic(fmt.Sprintf("Minima: %v", err))
+	}
+	log.Printf("Minima: %v", err)
+	r.mu.Lock()
+	r.errors = append(r.errors, err)
+	r.mu.Unlock()
+}
+
+/**
+ * @info Names the last added route so urls can be generated for it
+ * @param {string} [name] The route name
+ * @returns {*Router}
+ */
+func (r *Router) Name(name string) *Router {
+	r.mu.Lock()
+	last := r.last
+	other, taken := r.names[name]
+	if last != nil && (!taken || other == last) {
+		if last.name != "" && r.names[last.name] == last {
+			delete(r.names, last.name)
+		}
+		last.name = name
+		r.names[name] = last
+	}
+	r.mu.Unlock()
+	if last == nil {
+		r.report(fmt.Errorf("name %s must follow a registered route", name))
+	} else if taken && other != last {
+		r.report(fmt.Errorf("name %s of %s route %s is already used by %s route %s", name, last.method, last.path, other.method, other.path))
+	}
+	return r
+}
+
+/**
+ * @info Generates the url of a named route
+ * @param {string} [name] The route name
+ * @param {map[string]string} [params] The values for the route params
+ * @returns {string, error}
+ */
+func (r *Router) URL(name string, params map[string]string) (string, error) {
+	r.mu.Lock()
+	route, ok := r.names[name]
+	r.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("no route named %s", name)
+	}
+	segs, err := NewTree().parse(route.path)
+	if err != nil {
+		return "", err
+	}
+	var url strings.Builder
+	for _, s := range segs {
+		if s.kind == staticEdge {
+			url.WriteString(s.key)
+			continue
+		}
+		value, ok := params[s.name]
+		if !ok {
+			return "", fmt.Errorf("missing param %s for route %s", s.name, name)
+		}
+		if s.kind == paramEdge {
+			// A slash would end the segment and route the url somewhere else
+			if value == "" || strings.Contains(value, "/") || (s.match != nil && !s.match(value)) {
+				return "", fmt.Errorf("param %s=%q doesn't match route %s", s.name, value, route.path)
+			}
+			url.WriteString(neturl.PathEscape(value))
+			continue
+		}
+		// Only a catch-all spans several segments, so its slashes are kept
+		parts := strings.Split(value, "/")
+		for i, p := range parts {
+			parts[i] = neturl.PathEscape(p)
+		}
+		url.WriteString(strings.Join(parts, "/"))
+	}
+	return url.String(), nil
+}
+
+/**
+ * @info Injects the NotFound handler to the router
+ * @param {Handler} [handler] Minima handler instance
+ * @returns {*Router}
+ */
 func (r *Router) NotFound(handler Handler) *Router {
 	r.notfound = buildHandler(handler, nil)
 	return r
 }
 
+/**
+ * @info Injects the handler used when a path only matches under other methods
+ * @param {Handler} [handler] Minima handler instance
+ * @returns {*Router}
+ */
+func (r *Router) MethodNotAllowed(handler Handler) *Router {
+	r.methodnotallowed = buildHandler(handler, nil)
+	return r
+}
+
+/**
+ * @info Redirects paths missing only by a trailing slash, e.g. /users/ to /users and back
+ * @param {bool} [enabled] Whether the redirect is enabled
+ * @returns {*Router}
+ */
+func (r *Router) RedirectTrailingSlash(enabled bool) *Router {
+	r.redirectTrailingSlash = enabled
+	for _, h := range r.hostList() {
+		h.router.redirectTrailingSlash = enabled
+	}
+	return r
+}
+
+/**
+ * @info Redirects paths with extra slashes or dot segments to their cleaned form, e.g. //users/../users to /users
+ * @param {bool} [enabled] Whether the redirect is enabled
+ * @returns {*Router}
+ */
+func (r *Router) RedirectFixedPath(enabled bool) *Router {
+	r.redirectFixedPath = enabled
+	for _, h := range r.hostList() {
+		h.router.redirectFixedPath = enabled
+	}
+	return r
+}
+
+/**
+ * @info Matches static path segments regardless of case, should be set before serving
+ * @param {bool} [enabled] Whether case-insensitive matching is enabled
+ * @returns {*Router}
+ */
+func (r *Router) CaseInsensitive(enabled bool) *Router {
+	r.caseInsensitive = enabled
+	for _, routes := range r.trees() {
+		routes.fold = enabled
+	}
+	for _, h := range r.hostList() {
+		h.router.CaseInsensitive(enabled)
+	}
+	return r
+}
+
+/**
+ * @info Finds the cleaned or trailing slash toggled path a missed request should be redirected to
+ * @param {string} [method] The request method
+ * @param {string} [path] The request path
+ * @returns {string}
+ */
+func (r *Router) redirectPath(method string, path string) string {
+	if (!r.redirectTrailingSlash && !r.redirectFixedPath) || method == "CONNECT" || path == "/" {
+		return ""
+	}
+	candidates := make([]string, 0, 3)
+	if r.redirectTrailingSlash {
+		candidates = append(candidates, toggleSlash(path))
+	}
+	if r.redirectFixedPath {
+		clean := cleanPath(path)
+		candidates = append(candidates, clean)
+		if r.redirectTrailingSlash {
+			candidates = append(candidates, toggleSlash(clean))
+		}
+	}
+	for _, target := range candidates {
+		// A leading // or /\ would be followed by browsers as a different host
+		if target == path || (len(target) > 1 && (target[1] == '/' || target[1] == '\\')) {
+			continue
+		}
+		if n, _ := r.lookup(method, target, nil); n != nil {
+			return target
+		}
+	}
+	return ""
+}
+
+/**
+ * @info Adds or removes the trailing slash of a path
+ * @param {string} [path] The path to toggle
+ * @returns {string}
+ */
+func toggleSlash(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return path[:len(path)-1]
+	}
+	return path + "/"
+}
+
+/**
+ * @info Resolves dot segments and repeated slashes of a path, keeping its trailing slash
+ * @param {string} [p] The path to clean
+ * @returns {string}
+ */
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	clean := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && clean != "/" {
+		clean += "/"
+	}
+	return clean
+}
+
+/**
+ * @info Lists the methods whose trees match the given path, sorted for the Allow header
+ * @param {string} [path] The request path
+ * @returns {[]string}
+ */
+func (r *Router) allowed(path string) []string {
+	var methods []string
+	trees := r.trees()
+	found := make(map[string]bool, len(trees))
+	for method, routes := range trees {
+		if n := routes.GetNode(path, nil); n != nil {
+			methods = append(methods, method)
+			found[method] = true
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+	// HEAD is answered from the GET tree and OPTIONS from the Allow set itself
+	if found["GET"] && !found["HEAD"] {
+		methods = append(methods, "HEAD")
+	}
+	if !found["OPTIONS"] {
+		methods = append(methods, "OPTIONS")
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+/**
+ * @info Finds the route node for a request, answering HEAD from the GET tree
+ * @param {string} [method] The request method
+ * @param {string} [path] The request path
+ * @param {*Params} [params] The params to append the captured params to
+ * @returns {*Node, bool}
+ */
+func (r *Router) lookup(method string, path string, params *Params) (*Node, bool) {
+	trees := r.trees()
+	if routes, ok := trees[method]; ok {
+		if n := routes.GetNode(path, params); n != nil {
+			return n, false
+		}
+	}
+	if method == "HEAD" {
+		if n := trees["GET"].GetNode(path, params); n != nil {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
 /**
  * @info Adds route with Get method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Router}
  */
-func (r *Router) Get(path string, handler Handler) *Router {
-	r.Register("GET", path, handler)
+func (r *Router) Get(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	r.report(r.Register("GET", path, handler, middlewares...))
 	return r
 }
 
@@ -139,10 +608,11 @@ func (r *Router) Get(path string, handler Handler) *Router {
  * @info Adds route with Post method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Router}
  */
-func (r *Router) Post(path string, handler Handler) *Router {
-	r.Register("POST", path, handler)
+func (r *Router) Post(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	r.report(r.Register("POST", path, handler, middlewares...))
 	return r
 }
 
@@ -150,10 +620,11 @@ func (r *Router) Post(path string, handler Handler) *Router {
  * @info Adds route with Put method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Router}
  */
-func (r *Router) Put(path string, handler Handler) *Router {
-	r.Register("PUT", path, handler)
+func (r *Router) Put(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	r.report(r.Register("PUT", path, handler, middlewares...))
 	return r
 }
 
@@ -161,20 +632,23 @@ func (r *Router) Put(path string, handler Handler) *Router {
  * @info Adds route with Patch method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Router}
  */
-func (r *Router) Patch(path string, handler Handler) {
-	r.Register("PATCH", path, handler)
+func (r *Router) Patch(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	r.report(r.Register("PATCH", path, handler, middlewares...))
+	return r
 }
 
 /**
  * @info Adds route with Options method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Router}
  */
-func (r *Router) Options(path string, handler Handler) *Router {
-	r.Register("OPTIONS", path, handler)
+func (r *Router) Options(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	r.report(r.Register("OPTIONS", path, handler, middlewares...))
 	return r
 }
 
@@ -182,10 +656,11 @@ func (r *Router) Options(path string, handler Handler) *Router {
  * @info Adds route with Head method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Router}
  */
-func (r *Router) Head(path string, handler Handler) *Router {
-	r.Register("HEAD", path, handler)
+func (r *Router) Head(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	r.report(r.Register("HEAD", path, handler, middlewares...))
 	return r
 }
 
@@ -193,19 +668,98 @@ func (r *Router) Head(path string, handler Handler) *Router {
  * @info Adds route with Delete method
  * @param {string} [path] The route path
  * @param {...Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @returns {*Router}
+ */
+func (r *Router) Delete(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	r.report(r.Register("DELETE", path, handler, middlewares...))
+	return r
+}
+
+/**
+ * @info Adds route with any method, trees for extension methods such as PROPFIND or QUERY are created on first use
+ * @param {string} [method] The route method
+ * @param {string} [path] The route path
+ * @param {Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @returns {*Router}
+ */
+func (r *Router) Handle(method string, path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	r.report(r.Register(method, path, handler, middlewares...))
+	return r
+}
+
+/**
+ * @info Adds route with every standard method
+ * @param {string} [path] The route path
+ * @param {Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
+ * @returns {*Router}
+ */
+func (r *Router) Any(path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	return r.Match(methods, path, handler, middlewares...)
+}
+
+/**
+ * @info Adds route with each of the given methods
+ * @param {[]string} [methods] The route methods
+ * @param {string} [path] The route path
+ * @param {Handler} [handler] The handler for the given route
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping only this route
  * @returns {*Router}
  */
-func (r *Router) Delete(path string, handler Handler) *Router {
-	r.Register("DELETE", path, handler)
+func (r *Router) Match(methods []string, path string, handler Handler, middlewares ...func(http.Handler) http.Handler) *Router {
+	for _, method := range methods {
+		r.Handle(method, path, handler, middlewares...)
+	}
 	return r
 }
 
 /**
- * @info Returns all the routes in router
- * @returns {map[string][]*mux}
+ * @info Creates a group whose routes are merged along with the router's own routes
+ * @param {string} [prefix] The group prefix
+ * @returns {*Group}
+ */
+func (r *Router) Group(prefix string) *Group {
+	g := NewGroup(prefix)
+	r.groups = append(r.groups, g)
+	return g
+}
+
+/**
+ * @info Returns all the routes in router and its groups, wrapped with the router's own middlewares
+ * @returns {[]*cacheRoute}
  */
 func (r *Router) GetCacheRoutes() []*cacheRoute {
-	return r.cacheRoute
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// The core router's middlewares wrap every request instead of each route
+	if !r.isCache || (len(r.middlewares) == 0 && len(r.groups) == 0) {
+		return r.cacheRoute
+	}
+	routes := make([]*cacheRoute, 0, len(r.cacheRoute))
+	routes = append(routes, r.cacheRoute...)
+	for _, g := range r.groups {
+		routes = append(routes, g.GetGroupRoutes()...)
+	}
+	for i, v := range routes {
+		routes[i] = v.wrap(r.middlewares)
+	}
+	return routes
+}
+
+/**
+ * @info Takes the registration errors of the router's groups
+ * @returns {[]error}
+ */
+func (r *Router) groupErrors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []error
+	for _, g := range r.groups {
+		errs = append(errs, g.takeErrors()...)
+	}
+	return errs
 }
 
 /**
@@ -214,16 +768,114 @@ func (r *Router) GetCacheRoutes() []*cacheRoute {
  * @returns {Router}
  */
 func (r *Router) UseRouter(Router *Router) {
-	routes := Router.GetCacheRoutes()
-	if !r.isCache {
-		for _, v := range routes {
-			err := r.Register(v.method, v.path, v.handler); if err != nil {
-				panic(err)
-			}
+	r.Mount("", Router)
+}
+
+/**
+ * @info Appends all routes of a router re-rooted under a base path, along with its NotFound handler
+ * @param {string} [basePath] The path every route of the router is mounted under
+ * @param {Router} [router] The router instance to mount
+ * @returns {*Router}
+ */
+func (r *Router) Mount(basePath string, router *Router) *Router {
+	basePath = strings.TrimSuffix(basePath, "/")
+	for _, v := range router.GetCacheRoutes() {
+		route := *v
+		route.body = route.body.over(&router.body)
+		if basePath != "" && route.path == "/" {
+			route.path = basePath
+		} else {
+			route.path = basePath + route.path
 		}
+		r.report(r.add(&route))
+	}
+	for _, err := range router.groupErrors() {
+		r.report(err)
+	}
+	var mounts []*mount
+	for _, m := range router.mountList() {
+		mounts = append(mounts, &mount{prefix: basePath + m.prefix, notfound: chain(router.middlewares, m.notfound)})
+	}
+	if router.notfound != nil && basePath != "" {
+		mounts = append(mounts, &mount{prefix: basePath, notfound: chain(router.middlewares, router.notfound)})
+	}
+	r.addMounts(mounts)
+	return r
+}
+
+/**
+ * @info Publishes a copy of the mounts with more appended, so lookups keep reading the old slice meanwhile
+ * @param {[]*mount} [mounts] The mounts to append
+ * @returns {}
+ */
+func (r *Router) addMounts(mounts []*mount) {
+	if len(mounts) == 0 {
 		return
 	}
-	r.cacheRoute = append(r.cacheRoute, routes...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.mountList()
+	next := make([]*mount, 0, len(old)+len(mounts))
+	next = append(append(next, old...), mounts...)
+	r.mounts.Store(&next)
+}
+
+/**
+ * @info Returns the routers mounted under a base path
+ * @returns {[]*mount}
+ */
+func (r *Router) mountList() []*mount {
+	if mounts := r.mounts.Load(); mounts != nil {
+		return *mounts
+	}
+	return nil
+}
+
+/**
+ * @info Finds the NotFound handler of the deepest router mounted over the path
+ * @param {string} [path] The request path
+ * @param {*Router} [parent] The router to fall back to when a host router has none
+ * @returns {http.Handler}
+ */
+func (r *Router) notFoundFor(path string, parent *Router) http.Handler {
+	handler := r.notfound
+	depth := -1
+	for _, m := range r.mountList() {
+		if len(m.prefix) >= depth && (path == m.prefix || strings.HasPrefix(path, m.prefix+"/")) {
+			handler, depth = m.notfound, len(m.prefix)
+		}
+	}
+	if handler == nil && parent != r {
+		return parent.notFoundFor(path, parent)
+	}
+	return handler
+}
+
+/**
+ * @info Finds the MethodNotAllowed handler, falling back to the parent when a host router has none
+ * @param {*Router} [parent] The router to fall back to
+ * @returns {http.Handler}
+ */
+func (r *Router) methodNotAllowed(parent *Router) http.Handler {
+	if r.methodnotallowed == nil {
+		return parent.methodnotallowed
+	}
+	return r.methodnotallowed
+}
+
+/**
+ * @info Injects net/http middleware that only wraps this router's routes
+ * @param {...func(http.Handler)http.Handler} [handler] The handler stack to append
+ * @returns {*Router}
+ */
+func (r *Router) Use(handler ...func(http.Handler) http.Handler) *Router {
+	if r.isCache {
+		// Cached routers hand their middlewares to each route once they are merged
+		r.middlewares = append(r.middlewares, handler...)
+		return r
+	}
+	r.use(handler...)
+	return r
 }
 
 /**
@@ -232,18 +884,119 @@ func (r *Router) UseRouter(Router *Router) {
  * @returns {}
  */
 func (r *Router) use(handler ...func(http.Handler) http.Handler) {
-	if r.handler != nil {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sealed {
 		panic("Minima: Middlewares can't go after the routes are mounted")
 	}
 	r.middlewares = append(r.middlewares, handler...)
+	r.buildHandler()
+}
+
+/**
+ * @info Runs the request through the middleware stack and into the matched route
+ * @param {http.ResponseWriter} [w] The net/http response instance
+ * @param {http.Request} [rq] The net/http request instance
+ * @returns {}
+ */
+func (r *Router) ServeHTTP(w http.ResponseWriter, rq *http.Request) {
+	(*r.handler.Load()).ServeHTTP(w, rq)
 }
 
-// A dummy function that runs at the end of the middleware stack
-func (r *Router) middlewareHTTP(w http.ResponseWriter, rq *http.Request) {}
+/**
+ * @info The endpoint of the middleware stack which dispatches to the matched route
+ * @param {http.ResponseWriter} [w] The net/http response instance
+ * @param {http.Request} [rq] The net/http request instance
+ * @returns {}
+ */
+func (r *Router) routeHTTP(w http.ResponseWriter, rq *http.Request) {
+	c := contextPool.Get().(*routeContext)
+	defer c.release()
+	rt := r.hostRouter(rq.Host, &c.req.Params)
+	path := rq.URL.Path
+	if path == "" {
+		// CONNECT requests name an authority instead of a path and are matched as /
+		path = "/"
+	}
+	f, head := rt.lookup(rq.Method, path, &c.req.Params)
+	if head {
+		w = &headResponseWriter{w}
+	}
+
+	if f != nil && len(f.versions) > 0 {
+		varyOnVersion(w.Header())
+		if f = f.version(r.requestVersion(rq)); f == nil {
+			w.WriteHeader(http.StatusNotAcceptable)
+			w.Write([]byte("No matching version found"))
+			return
+		}
+	}
+
+	if f != nil {
+		chained := f.chain()
+		req := &c.req
+		if shared, ok := rq.Context().Value(requestKey{}).(*Request); ok {
+			// Keep the instance earlier middlewares stored their locals on
+			shared.ref = rq
+			shared.Params = append(shared.Params[:0], c.req.Params...)
+			req = shared
+		} else {
+			req.reset(rq)
+		}
+		if chained != nil && req == &c.req {
+			// Route middlewares such as http.TimeoutHandler may hand the request to a goroutine outliving this call, so it isn't taken from the pool
+			req = &Request{Params: append(Params(nil), c.req.Params...)}
+			req.reset(rq)
+		}
+		limit, maxMemory, noSpill := r.body.resolve(f.body)
+		if limit > 0 {
+			w = limitBody(w, rq, limit)
+		}
+		req.maxMemory, req.noSpill, req.decoders, req.routeDecoders = maxMemory, noSpill, r.body.decoders, nil
+		if f.body != nil {
+			req.routeDecoders = f.body.decoders
+		}
+		if chained != nil {
+			chained.ServeHTTP(w, req.share().ref)
+		} else if !rejectTooLarge(w, rq) {
+			c.res.reset(w, rq)
+			f.handler(&c.res, req)
+		}
+		req.cleanup()
+	} else if target := rt.redirectPath(rq.Method, path); target != "" {
+		code := http.StatusMovedPermanently
+		if rq.Method != "GET" && rq.Method != "HEAD" {
+			// 308 keeps the method and body, which browsers drop on a 301
+			code = http.StatusPermanentRedirect
+		}
+		// The target is a decoded path, escaping keeps bytes such as ? and \ from changing the Location
+		location := (&neturl.URL{Path: target}).EscapedPath()
+		if rq.URL.RawQuery != "" {
+			location += "?" + rq.URL.RawQuery
+		}
+		http.Redirect(w, rq, location, code)
+	} else if allow := rt.allowed(path); len(allow) > 0 {
+		w.Header().Set("Allow", strings.Join(allow, ", "))
+		if rq.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusNoContent)
+		} else if mna := rt.methodNotAllowed(r); mna != nil {
+			mna.ServeHTTP(w, rq)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			w.Write([]byte("Method not allowed"))
+		}
+	} else if notfound := rt.notFoundFor(path, r); notfound != nil {
+		notfound.ServeHTTP(w, rq)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("No matching route found"))
+	}
+}
 
 /**
- * @info Builds whole middleware stack chain into single handler
+ * @info Builds whole middleware stack chain into single handler ending in the router
  */
 func (r *Router) buildHandler() {
-	r.handler = chain(r.middlewares, http.HandlerFunc(r.middlewareHTTP))
+	handler := chain(r.middlewares, http.HandlerFunc(r.routeHTTP))
+	r.handler.Store(&handler)
 }
@@ -0,0 +1,522 @@
+package minima
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegisterWhileServing(t *testing.T) {
+	app := Engine()
+	var wg, ready sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		ready.Add(1)
+		go func() {
+			defer wg.Done()
+			serve(app, "GET", "/missing/path")
+			ready.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				serve(app, "GET", "/missing/path")
+				serve(app, "GET", "/r0/a")
+			}
+		}()
+	}
+	ready.Wait()
+
+	for i := 0; i < 200; i++ {
+		router := NewRouter()
+		router.Get("/a", ok)
+		router.NotFound(func(res *Response, req *Request) {
+			res.Status(404).Send("mounted")
+		})
+		app.Mount(fmt.Sprintf("/r%d", i), router)
+		app.Host(fmt.Sprintf("h%d.example.com", i), router)
+		app.Get(fmt.Sprintf("/p%d", i), ok)
+		if i%2 == 0 {
+			app.Remove("GET", fmt.Sprintf("/p%d", i))
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if w := serve(app, "GET", "/r7/missing"); w.Body.String() != "mounted" {
+		t.Errorf("mounted NotFound: got %q", w.Body.String())
+	}
+	if w := serve(app, "GET", "/p1"); w.Body.String() != "ok" {
+		t.Errorf("added route: got %d %q", w.Code, w.Body.String())
+	}
+	if w := serve(app, "GET", "/p2"); w.Code != 404 {
+		t.Errorf("removed route: got %d", w.Code)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	app := Engine()
+	app.Get("/users/:id", ok)
+	app.Delete("/users/:id", ok)
+	app.Post("/users", ok)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		allow  string
+	}{
+		{"PUT", "/users/1", 405, "DELETE, GET, HEAD, OPTIONS"},
+		{"GET", "/users", 405, "OPTIONS, POST"},
+		{"OPTIONS", "/users/1", 204, "DELETE, GET, HEAD, OPTIONS"},
+		{"GET", "/users/1", 200, ""},
+		{"PUT", "/missing", 404, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := serve(app, tt.method, tt.path)
+			if w.Code != tt.status || w.Header().Get("Allow") != tt.allow {
+				t.Errorf("got %d Allow %q, want %d Allow %q", w.Code, w.Header().Get("Allow"), tt.status, tt.allow)
+			}
+		})
+	}
+
+	app.MethodNotAllowed(func(res *Response, req *Request) {
+		res.Status(405).Send("custom")
+	})
+	if w := serve(app, "PUT", "/users/1"); w.Code != 405 || w.Body.String() != "custom" || w.Header().Get("Allow") == "" {
+		t.Errorf("custom handler: got %d %q Allow %q", w.Code, w.Body.String(), w.Header().Get("Allow"))
+	}
+}
+
+func TestHeadAndOptions(t *testing.T) {
+	app := Engine()
+	app.Get("/page", func(res *Response, req *Request) {
+		res.SetHeader("X-Page", "1")
+		res.Send("body")
+	})
+	app.Head("/own", func(res *Response, req *Request) {
+		res.SetHeader("X-Own", "1").Send("own")
+	})
+	app.Options("/page", func(res *Response, req *Request) {
+		res.Status(200).Send("options")
+	})
+
+	if w := serve(app, "HEAD", "/page"); w.Code != 200 || w.Body.Len() != 0 || w.Header().Get("X-Page") != "1" {
+		t.Errorf("HEAD from GET: got %d %q headers %v", w.Code, w.Body.String(), w.Header())
+	}
+	if w := serve(app, "HEAD", "/own"); w.Header().Get("X-Own") != "1" {
+		t.Errorf("HEAD route: got headers %v", w.Header())
+	}
+	if w := serve(app, "OPTIONS", "/page"); w.Code != 200 || w.Body.String() != "options" {
+		t.Errorf("OPTIONS route: got %d %q", w.Code, w.Body.String())
+	}
+	if w := serve(app, "OPTIONS", "/own"); w.Code != 204 || w.Header().Get("Allow") != "HEAD, OPTIONS" {
+		t.Errorf("automatic OPTIONS: got %d Allow %q", w.Code, w.Header().Get("Allow"))
+	}
+}
+
+// Serves a request built by the caller, for tests setting headers or the host
+func do(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestRedirects(t *testing.T) {
+	app := Engine().RedirectTrailingSlash(true).RedirectFixedPath(true)
+	app.Get("/users", ok)
+	app.Get("/docs/", ok)
+	app.Post("/orders", ok)
+	app.Get("/files/:name", ok)
+
+	tests := []struct {
+		method   string
+		target   string
+		status   int
+		location string
+	}{
+		{"GET", "/users/", 301, "/users"},
+		{"GET", "/docs", 301, "/docs/"},
+		{"GET", "/users/?page=2", 301, "/users?page=2"},
+		{"HEAD", "/users/", 301, "/users"},
+		{"POST", "/orders/", 308, "/orders"},
+		{"GET", "/a/../users", 301, "/users"},
+		{"GET", "//users", 301, "/users"},
+		{"GET", "/a/../users/", 301, "/users"},
+		{"GET", "/files/a b/", 301, "/files/a%20b"},
+		{"GET", "/files/100%/", 301, "/files/100%25"},
+		{"GET", "/users", 200, ""},
+		{"GET", "/missing/", 404, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			// Set the raw path so the test client doesn't clean it first
+			req.URL.Path, req.URL.RawQuery, _ = strings.Cut(tt.target, "?")
+			w := do(app, req)
+			if w.Code != tt.status || w.Header().Get("Location") != tt.location {
+				t.Errorf("got %d %q, want %d %q", w.Code, w.Header().Get("Location"), tt.status, tt.location)
+			}
+		})
+	}
+
+	// Decoded paths starting with /\ or // are read by browsers as another host
+	open := Engine().RedirectTrailingSlash(true).RedirectFixedPath(true)
+	open.Get("/:name", ok)
+	for _, target := range []string{"/%5Cevil.com/", "/%5C%5Cevil.com/"} {
+		if w := serve(open, "GET", target); w.Code != 404 || w.Header().Get("Location") != "" {
+			t.Errorf("%s: got %d %q, want no redirect", target, w.Code, w.Header().Get("Location"))
+		}
+	}
+
+	off := Engine()
+	off.Get("/users", ok)
+	if w := serve(off, "GET", "/users/"); w.Code != 404 {
+		t.Errorf("redirects off: got %d", w.Code)
+	}
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	app := Engine().CaseInsensitive(true)
+	app.Get("/Users/:name", func(res *Response, req *Request) {
+		res.Send(req.Param("name"))
+	})
+	if w := serve(app, "GET", "/users/Ada"); w.Code != 200 || w.Body.String() != "Ada" {
+		t.Errorf("got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestHosts(t *testing.T) {
+	api := NewRouter()
+	api.Get("/users", func(res *Response, req *Request) {
+		res.Send("api")
+	})
+	tenant := NewRouter()
+	tenant.Get("/users", func(res *Response, req *Request) {
+		res.Send("tenant " + req.Param("tenant"))
+	})
+	tenant.NotFound(func(res *Response, req *Request) {
+		res.Status(404).Send("tenant missing")
+	})
+	app := Engine()
+	app.Get("/users", func(res *Response, req *Request) {
+		res.Send("main")
+	})
+	app.Host("api.example.com", api)
+	app.Host(":tenant.example.com", tenant)
+
+	tests := []struct {
+		host   string
+		path   string
+		status int
+		body   string
+	}{
+		{"api.example.com", "/users", 200, "api"},
+		{"API.example.com:8080", "/users", 200, "api"},
+		{"acme.example.com", "/users", 200, "tenant acme"},
+		{"acme.example.com", "/nope", 404, "tenant missing"},
+		{"example.com", "/users", 200, "main"},
+		{"a.b.example.com", "/users", 200, "main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Host = tt.host
+			w := do(app, req)
+			if w.Code != tt.status || w.Body.String() != tt.body {
+				t.Errorf("got %d %q, want %d %q", w.Code, w.Body.String(), tt.status, tt.body)
+			}
+		})
+	}
+
+	if errs := Engine().Host("a..com", api).Errors(); len(errs) != 1 {
+		t.Errorf("empty label: got errors %v", errs)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	reply := func(body string) Handler {
+		return func(res *Response, req *Request) {
+			res.Send(body)
+		}
+	}
+	app := Engine()
+	app.Get("/users", reply("any"))
+	app.Version("1").Get("/users", reply("v1"))
+	app.Version("v2").Get("/users", reply("v2"))
+	app.Version("2").Get("/orders", reply("orders v2"))
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		value  string
+		status int
+		body   string
+	}{
+		{"header", "/users", "API-Version", "2", 200, "v2"},
+		{"prefixed header", "/users", "API-Version", "v1", 200, "v1"},
+		{"accept vendor type", "/users", "Accept", "text/html, application/vnd.acme.v2+json", 200, "v2"},
+		{"unknown version falls back", "/users", "API-Version", "9", 200, "any"},
+		{"no version falls back", "/users", "", "", 200, "any"},
+		{"versioned only", "/orders", "API-Version", "2", 200, "orders v2"},
+		{"versioned only without version", "/orders", "", "", 406, "No matching version found"},
+		{"versioned only with another version", "/orders", "API-Version", "1", 406, "No matching version found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := do(app, req)
+			if w.Code != tt.status || w.Body.String() != tt.body {
+				t.Errorf("got %d %q, want %d %q", w.Code, w.Body.String(), tt.status, tt.body)
+			}
+			if w.Header().Get("Vary") != "Accept, API-Version" {
+				t.Errorf("Vary %q", w.Header().Get("Vary"))
+			}
+		})
+	}
+
+	app.DefaultVersion("2")
+	if w := serve(app, "GET", "/orders"); w.Body.String() != "orders v2" {
+		t.Errorf("default version: got %d %q", w.Code, w.Body.String())
+	}
+	if w := serve(app, "GET", "/users"); w.Body.String() != "v2" {
+		t.Errorf("default version: got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestURL(t *testing.T) {
+	app := Engine()
+	app.Get("/users/:id<int>", ok).Name("user.show")
+	app.Get("/users/:id/posts/:slug", ok).Name("post.show")
+	app.Get("/files/*path", ok).Name("files")
+	app.Get("/static/*", ok).Name("static")
+	app.Get("/about", ok).Name("about")
+	api := NewGroup("/api")
+	api.Get("/items/:id", ok).Name("item.show")
+	app.UseGroup(api)
+
+	tests := []struct {
+		name   string
+		route  string
+		params map[string]string
+		url    string
+	}{
+		{"static route", "about", nil, "/about"},
+		{"typed param", "user.show", map[string]string{"id": "42"}, "/users/42"},
+		{"two params", "post.show", map[string]string{"id": "ada", "slug": "hello world"}, "/users/ada/posts/hello%20world"},
+		{"param escaped", "post.show", map[string]string{"id": "a?b", "slug": "100%"}, "/users/a%3Fb/posts/100%25"},
+		{"catch-all keeps slashes", "files", map[string]string{"path": "docs/a b.txt"}, "/files/docs/a%20b.txt"},
+		{"unnamed catch-all", "static", map[string]string{"*": "css/app.css"}, "/static/css/app.css"},
+		{"empty catch-all", "files", map[string]string{"path": ""}, "/files/"},
+		{"group route", "item.show", map[string]string{"id": "7"}, "/api/items/7"},
+		{"missing param", "post.show", map[string]string{"id": "ada"}, ""},
+		{"constraint violated", "user.show", map[string]string{"id": "ada"}, ""},
+		{"empty param", "user.show", map[string]string{"id": ""}, ""},
+		{"slash in param", "post.show", map[string]string{"id": "a/b", "slug": "c"}, ""},
+		{"unknown name", "nope", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, err := app.URL(tt.route, tt.params)
+			if tt.url == "" && err == nil {
+				t.Errorf("got %q, want an error", url)
+			}
+			if tt.url != "" && (err != nil || url != tt.url) {
+				t.Errorf("got %q %v, want %q", url, err, tt.url)
+			}
+			// A generated url has to route back to the route it was generated for
+			if tt.url != "" && serve(app, "GET", url).Code != 200 {
+				t.Errorf("%s doesn't route back", url)
+			}
+		})
+	}
+}
+
+func TestDuplicateNames(t *testing.T) {
+	app := Engine()
+	app.Get("/a", ok).Name("page")
+	app.Get("/b", ok).Name("page")
+	g := NewGroup("/g")
+	g.Get("/c", ok).Name("page")
+	app.UseGroup(g)
+
+	if errs := app.Errors(); len(errs) != 2 {
+		t.Errorf("got errors %v, want 2", errs)
+	}
+	if url, _ := app.URL("page", nil); url != "/a" {
+		t.Errorf("name moved to %s", url)
+	}
+	if serve(app, "GET", "/g/c").Code != 200 {
+		t.Error("route with a taken name wasn't registered")
+	}
+
+	// Renaming the last route frees its old name
+	app.Get("/d", ok).Name("d").Name("e")
+	if _, err := app.URL("d", nil); err == nil {
+		t.Error("old name still resolves")
+	}
+	if url, _ := app.URL("e", nil); url != "/d" {
+		t.Errorf("renamed route: got %s", url)
+	}
+}
+
+func TestCustomMethods(t *testing.T) {
+	reply := func(res *Response, req *Request) {
+		res.Send(req.Method() + " " + req.Param("file"))
+	}
+	app := Engine()
+	if _, ok := app.router.trees()["PROPFIND"]; ok {
+		t.Fatal("PROPFIND tree exists before any route")
+	}
+	app.Handle("PROPFIND", "/dav/:file", reply)
+	app.Handle("bad method", "/dav", reply)
+	app.Handle("", "/dav", reply)
+	app.Handle("CONNECT", "/", reply)
+	app.Any("/any/:file", reply)
+	app.Match([]string{"GET", "POST", "REPORT"}, "/match/:file", reply)
+	g := NewGroup("/g")
+	g.Handle("MKCOL", "/:file", reply)
+	g.Any("/any/:file", reply)
+	g.Match([]string{"PUT", "LOCK"}, "/match/:file", reply)
+	app.UseGroup(g)
+
+	if _, ok := app.router.trees()["PROPFIND"]; !ok {
+		t.Error("PROPFIND tree wasn't created")
+	}
+	if errs := app.Errors(); len(errs) != 2 {
+		t.Errorf("got errors %v, want the two invalid methods", errs)
+	}
+	if _, ok := app.router.trees()["bad method"]; ok {
+		t.Error("tree created for an invalid method")
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+		allow  string
+	}{
+		{"PROPFIND", "/dav/a", 200, "PROPFIND a", ""},
+		{"GET", "/dav/a", 405, "Method not allowed", "OPTIONS, PROPFIND"},
+		{"OPTIONS", "/dav/a", 204, "", "OPTIONS, PROPFIND"},
+		{"DELETE", "/any/a", 200, "DELETE a", ""},
+		// Any registers OPTIONS too, so the handler answers instead of the automatic Allow reply
+		{"OPTIONS", "/any/a", 200, "OPTIONS a", ""},
+		{"PROPFIND", "/any/a", 405, "Method not allowed", "DELETE, GET, HEAD, OPTIONS, PATCH, POST, PUT"},
+		{"REPORT", "/match/a", 200, "REPORT a", ""},
+		{"PUT", "/match/a", 405, "Method not allowed", "GET, HEAD, OPTIONS, POST, REPORT"},
+		{"MKCOL", "/g/a", 200, "MKCOL a", ""},
+		{"OPTIONS", "/g/any/a", 200, "OPTIONS a", ""},
+		{"LOCK", "/g/match/a", 200, "LOCK a", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := serve(app, tt.method, tt.path)
+			if w.Code != tt.status || w.Body.String() != tt.body || w.Header().Get("Allow") != tt.allow {
+				t.Errorf("got %d %q Allow %q, want %d %q Allow %q", w.Code, w.Body.String(), w.Header().Get("Allow"), tt.status, tt.body, tt.allow)
+			}
+		})
+	}
+
+	// CONNECT names an authority instead of a path and is matched as /
+	req := httptest.NewRequest("CONNECT", "/", nil)
+	req.URL = &url.URL{Host: "example.com:443"}
+	if w := do(app, req); w.Code != 200 || w.Body.String() != "CONNECT " {
+		t.Errorf("CONNECT: got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestMount(t *testing.T) {
+	mark := func(name string) func(http.Handler) http.Handler {
+		return Middleware(func(res *Response, req *Request) {
+			res.Raw().Header().Add("X-Router", name)
+		})
+	}
+	notFound := func(body string) Handler {
+		return func(res *Response, req *Request) {
+			res.Status(404).Send(body)
+		}
+	}
+	reply := func(body string) Handler {
+		return func(res *Response, req *Request) {
+			res.Send(body + req.Param("id"))
+		}
+	}
+
+	inner := NewRouter()
+	inner.Use(mark("inner"))
+	inner.Get("/", reply("inner root")).Name("inner.root")
+	inner.Get("/items/:id", reply("item ")).Name("inner.item")
+	inner.NotFound(notFound("inner missing"))
+
+	billing := NewRouter()
+	billing.Use(mark("billing"))
+	billing.Get("/", reply("billing root")).Name("billing")
+	billing.Get("/invoices/:id", reply("invoice ")).Name("invoice")
+	billing.NotFound(notFound("billing missing"))
+	billing.Mount("/v1", inner)
+
+	app := Engine()
+	app.Get("/", reply("root"))
+	app.Mount("/billing/", billing)
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+		chain  string
+	}{
+		{"/", 200, "root", ""},
+		{"/billing", 200, "billing root", "billing"},
+		{"/billing/invoices/7", 200, "invoice 7", "billing"},
+		{"/billing/v1", 200, "inner root", "billing,inner"},
+		{"/billing/v1/items/3", 200, "item 3", "billing,inner"},
+		{"/billing/nope", 404, "billing missing", "billing"},
+		{"/billing/v1/nope", 404, "inner missing", "billing,inner"},
+		{"/nope", 404, "No matching route found", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := serve(app, "GET", tt.path)
+			if w.Code != tt.status || w.Body.String() != tt.body {
+				t.Errorf("got %d %q, want %d %q", w.Code, w.Body.String(), tt.status, tt.body)
+			}
+			// A mounted router's middlewares run inside the ones of the router it is mounted on
+			if got := strings.Join(w.Header().Values("X-Router"), ","); got != tt.chain {
+				t.Errorf("middlewares %q, want %q", got, tt.chain)
+			}
+		})
+	}
+
+	urls := map[string]string{
+		"billing":    "/billing",
+		"invoice":    "/billing/invoices/7",
+		"inner.root": "/billing/v1",
+		"inner.item": "/billing/v1/items/7",
+	}
+	for name, want := range urls {
+		if got, err := app.URL(name, map[string]string{"id": "7"}); got != want {
+			t.Errorf("%s: got %q %v, want %q", name, got, err, want)
+		}
+	}
+}
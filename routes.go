@@ -0,0 +1,133 @@
+package minima
+
+/**
+* Minima is a free and open source software under Mit license
+
+Copyright (c) 2024 gominima
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+* Authors @apoorvcodes @megatank58
+* Maintainers @Panquesito7 @savioxavier @Shubhaankar-Sharma @apoorvcodes @megatank58
+* Thank you for showing interest in minima and for this beautiful community
+*/
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+)
+
+/**
+ * @info A registered route as reported by the router
+ * @property {string} [Method] The route method
+ * @property {string} [Host] The host pattern the route is served for, empty for any host
+ * @property {string} [Pattern] The full route path after merges and mounts
+ * @property {[]string} [Params] The names of the route params
+ * @property {string} [Version] The api version the route is served for, empty for every version
+ * @property {string} [Name] The route name, if any
+ * @property {string} [Handler] The name of the handler function
+ * @property {int} [Middlewares] The number of middlewares wrapping the handler
+ */
+type RouteInfo struct {
+	Method      string
+	Host        string
+	Pattern     string
+	Params      []string
+	Version     string
+	Name        string
+	Handler     string
+	Middlewares int
+}
+
+/**
+ * @info Lists every registered route in registration order
+ * @returns {[]RouteInfo}
+ */
+func (r *Router) Routes() []RouteInfo {
+	routes := r.GetCacheRoutes()
+	global := 0
+	if !r.isCache {
+		global = len(r.middlewares)
+	}
+	infos := make([]RouteInfo, 0, len(routes))
+	for _, v := range routes {
+		info := RouteInfo{
+			Method:      v.method,
+			Pattern:     v.path,
+			Version:     v.version,
+			Name:        v.name,
+			Handler:     handlerName(v.handler),
+			Middlewares: global + len(v.middlewares),
+		}
+		if v.group != nil {
+			if stack := v.group.stack.Load(); stack != nil {
+				info.Middlewares += len(*stack)
+			}
+		}
+		segs, _ := NewTree().parse(v.path)
+		for _, s := range segs {
+			if s.kind != staticEdge {
+				info.Params = append(info.Params, s.name)
+			}
+		}
+		infos = append(infos, info)
+	}
+	for _, h := range r.hostList() {
+		for _, info := range h.router.Routes() {
+			info.Host = h.pattern
+			info.Middlewares += global
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+/**
+ * @info Prints the route table
+ * @param {io.Writer} [w] The writer to print to
+ * @returns {error}
+ */
+func (r *Router) PrintRoutes(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATTERN\tVERSION\tNAME\tHANDLER\tMIDDLEWARES")
+	for _, v := range r.Routes() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\n", v.Method, v.Host+v.Pattern, v.Version, v.Name, v.Handler, v.Middlewares)
+	}
+	return tw.Flush()
+}
+
+/**
+ * @info Gets the package qualified name of a handler function
+ * @param {Handler} [h] The handler
+ * @returns {string}
+ */
+func handlerName(h Handler) string {
+	if h == nil {
+		return ""
+	}
+	fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	return strings.TrimPrefix(fn.Name(), "github.com/")
+}
@@ -0,0 +1,109 @@
+package minima
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func pass(next http.Handler) http.Handler {
+	return next
+}
+
+// Builds an app with merged, mounted, grouped, versioned and host routes
+func routesApp() *Minima {
+	app := Engine()
+	app.UseRaw(pass)
+	app.Get("/users/:id<int>", ok, pass).Name("user.show")
+
+	billing := NewRouter()
+	billing.Use(pass)
+	billing.Get("/", ok).Name("billing")
+	admin := billing.Group("/admin")
+	admin.Use(pass)
+	admin.Get("/invoices/:invoice", ok)
+	app.Mount("/billing", billing)
+
+	api := NewGroup("/api")
+	api.Use(pass)
+	api.Group("/files").Get("/*path", ok)
+	app.UseGroup(api)
+
+	app.Version("2").Get("/reports", ok)
+	v3 := app.Version("3")
+	v3.Get("/reports", ok)
+	v3.Use(pass)
+
+	tenant := NewRouter()
+	tenant.Get("/home/:page", ok, pass)
+	app.Host(":tenant.example.com", tenant)
+	return app
+}
+
+func TestRoutes(t *testing.T) {
+	want := []RouteInfo{
+		{Method: "GET", Pattern: "/users/:id<int>", Params: []string{"id"}, Name: "user.show", Middlewares: 2},
+		{Method: "GET", Pattern: "/billing", Name: "billing", Middlewares: 2},
+		{Method: "GET", Pattern: "/billing/admin/invoices/:invoice", Params: []string{"invoice"}, Middlewares: 3},
+		{Method: "GET", Pattern: "/api/files/*path", Params: []string{"path"}, Middlewares: 2},
+		{Method: "GET", Pattern: "/reports", Version: "2", Middlewares: 1},
+		{Method: "GET", Pattern: "/reports", Version: "3", Middlewares: 2},
+		{Method: "GET", Host: ":tenant.example.com", Pattern: "/home/:page", Params: []string{"page"}, Middlewares: 2},
+	}
+
+	got := routesApp().Routes()
+	for i := range got {
+		if !strings.HasSuffix(got[i].Handler, "minima.ok") {
+			t.Errorf("%s: handler %q", got[i].Pattern, got[i].Handler)
+		}
+		got[i].Handler = ""
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestPrintRoutes(t *testing.T) {
+	var b bytes.Buffer
+	if err := routesApp().PrintRoutes(&b); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	want := [][]string{
+		{"METHOD", "PATTERN", "VERSION", "NAME", "HANDLER", "MIDDLEWARES"},
+		{"GET", "/users/:id<int>", "user.show", "gominima/minima.ok", "2"},
+		{"GET", "/billing", "billing", "gominima/minima.ok", "2"},
+		{"GET", "/billing/admin/invoices/:invoice", "gominima/minima.ok", "3"},
+		{"GET", "/api/files/*path", "gominima/minima.ok", "2"},
+		{"GET", "/reports", "2", "gominima/minima.ok", "1"},
+		{"GET", "/reports", "3", "gominima/minima.ok", "2"},
+		{"GET", ":tenant.example.com/home/:page", "gominima/minima.ok", "2"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines:\n%s", len(lines), b.String())
+	}
+	for i, line := range lines {
+		if fields := strings.Fields(line); !reflect.DeepEqual(fields, want[i]) {
+			t.Errorf("line %d: got %q, want %q", i, fields, want[i])
+		}
+	}
+	// Columns are aligned on the header
+	if col := strings.Index(lines[0], "PATTERN"); strings.Index(lines[1], "/users") != col {
+		t.Errorf("pattern column not aligned:\n%s", b.String())
+	}
+}
+
+func TestBanner(t *testing.T) {
+	var b bytes.Buffer
+	app := routesApp()
+	app.printBanner(&b, ":3000")
+	if b.Len() != 0 {
+		t.Errorf("banner printed while disabled:\n%s", b.String())
+	}
+	app.Banner(true).printBanner(&b, ":3000")
+	if !strings.HasPrefix(b.String(), "METHOD") || !strings.Contains(b.String(), "/billing/admin/invoices/:invoice") {
+		t.Errorf("banner doesn't print the route table:\n%s", b.String())
+	}
+}
@@ -29,230 +29,503 @@ SOFTWARE.
 */
 
 import (
+	"fmt"
+	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 /**
- * @info the radix tree structure
- * @property {Node} [roots] The roots of the tree
+ * @info the radix tree structure, read without locks while writers fork the changed path and swap the root
+ * @property {atomic.Pointer[Node]} [root] The published root of the tree
  * @property {int} [len] The lenght of the tree
  * @property {int} [size] The size of the tree
- * @property {bool} [safe] Whether the mutex is enabled or not
+ * @property {bool} [safe] Whether writes are serialised by the mutex or not
  * @property {byte} [placeholder] The regex byte for params
+ * @property {byte} [wildcard] The regex byte for catch-all segments
  * @property {byte} [delim] The regex byte for params
- * @property {sync.Mutex} [mu] The synx.Mutex instance
+ * @property {bool} [fold] Whether static segments match case-insensitively
+ * @property {sync.Mutex} [mu] The synx.Mutex instance guarding writers
  */
 type tree struct {
-	root        *Node
+	root        atomic.Pointer[Node]
 	len         int
 	size        int
 	safe        bool
 	placeholder byte
+	wildcard    byte
 	delim       byte
+	fold        bool
 	mu          *sync.Mutex
 }
 
+/**
+ * @info A single parsed segment of a route path
+ * @property {edgeKind} [kind] The kind of the segment
+ * @property {string} [key] The raw key of the segment
+ * @property {string} [name] The param name for dynamic segments
+ * @property {constraint} [match] The optional constraint of a param segment
+ */
+type segment struct {
+	kind  edgeKind
+	key   string
+	name  string
+	match constraint
+}
+
 /**
  * @info Creates a new radix tree
  */
 func NewTree() *tree {
-	return &tree{
-		root:        &Node{},
+	tr := &tree{
 		len:         1,
 		placeholder: ':',
+		wildcard:    '*',
 		delim:       '/',
 		mu:          &sync.Mutex{},
 		safe:        true,
 	}
+	tr.root.Store(&Node{})
+	return tr
+}
+
+/**
+ * @info Splits a route path into static, param and catch-all segments
+ * @param {string} [key] The route path to split
+ * @returns {[]segment, error}
+ */
+func (tr *tree) parse(key string) ([]segment, error) {
+	var segs []segment
+	start := 0
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if (c != tr.placeholder && c != tr.wildcard) || (i > 0 && key[i-1] != tr.delim) {
+			continue
+		}
+		if i > start {
+			segs = append(segs, segment{kind: staticEdge, key: key[start:i]})
+		}
+		if c == tr.wildcard {
+			if strings.IndexByte(key[i:], tr.delim) >= 0 {
+				return nil, fmt.Errorf("catch-all segment %q must be at the end of route %s", key[i:], key)
+			}
+			name := key[i+1:]
+			if name == "" {
+				name = "*"
+			}
+			segs = append(segs, segment{kind: catchAllEdge, key: key[i:], name: name})
+			return segs, nil
+		}
+		s, end, err := tr.parseParam(key, i)
+		if err != nil {
+			return nil, err
+		}
+		segs = append(segs, s)
+		start = end
+		i = end - 1
+	}
+	if start < len(key) {
+		segs = append(segs, segment{kind: staticEdge, key: key[start:]})
+	}
+	return segs, nil
+}
+
+/**
+ * @info Parses a `:name` or `:name<constraint>` segment starting at the placeholder
+ * @param {string} [key] The route path
+ * @param {int} [i] The index of the placeholder byte
+ * @returns {segment, int, error}
+ */
+func (tr *tree) parseParam(key string, i int) (segment, int, error) {
+	end := i + 1
+	for end < len(key) && key[end] != tr.delim && key[end] != '<' {
+		end++
+	}
+	s := segment{kind: paramEdge, name: key[i+1 : end]}
+	if s.name == "" {
+		return s, 0, fmt.Errorf("param segment in route %s has no name", key)
+	}
+	if end < len(key) && key[end] == '<' {
+		open := end
+		depth := 0
+		for ; end < len(key); end++ {
+			if key[end] == '<' {
+				depth++
+			} else if key[end] == '>' {
+				if depth--; depth == 0 {
+					break
+				}
+			}
+		}
+		if end == len(key) {
+			return s, 0, fmt.Errorf("unterminated constraint for param %s in route %s", s.name, key)
+		}
+		match, err := compileConstraint(key[open+1 : end])
+		if err != nil {
+			return s, 0, fmt.Errorf("param %s in route %s: %w", s.name, key, err)
+		}
+		s.match = match
+		end++
+		if end < len(key) && key[end] != tr.delim {
+			return s, 0, fmt.Errorf("constraint for param %s in route %s must end its segment", s.name, key)
+		}
+	}
+	s.key = key[i:end]
+	return s, end, nil
 }
 
 /**
  * @info Inserts a new node in the tree
  * @param {string} [key] The route path used as key
  * @param {Handler} [handler] The handler to be used
- * @returns {}
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping the handler
+ * @returns {error}
+ */
+func (tr *tree) InsertNode(key string, handler Handler, middlewares ...func(http.Handler) http.Handler) error {
+	return tr.insert(key, "", &Node{handler: handler, middlewares: middlewares})
+}
+
+/**
+ * @info Inserts a handler only served for an api version of the route
+ * @param {string} [key] The route path used as key
+ * @param {string} [version] The api version
+ * @param {Handler} [handler] The handler to be used
+ * @param {...func(http.Handler)http.Handler} [middlewares] The middlewares wrapping the handler
+ * @returns {error}
+ */
+func (tr *tree) InsertVersion(key string, version string, handler Handler, middlewares ...func(http.Handler) http.Handler) error {
+	return tr.insert(key, version, &Node{handler: handler, middlewares: middlewares})
+}
+
+/**
+ * @info Inserts a route record along with the body settings it carries
+ * @param {*cacheRoute} [route] The route to insert
+ * @returns {error}
  */
-func (tr *tree) InsertNode(key string, handler Handler) {
-	if key == "" || handler == nil {
-		return
+func (tr *tree) insertRoute(route *cacheRoute) error {
+	return tr.insert(route.path, route.version, &Node{handler: route.handler, middlewares: route.middlewares, body: route.body, group: route.group})
+}
+
+/**
+ * @info Inserts the handler of a route, for every version when the version is empty
+ * @param {string} [key] The route path used as key
+ * @param {string} [version] The api version
+ * @param {*Node} [leaf] The node holding the handler, its middlewares and body settings
+ * @returns {error}
+ */
+func (tr *tree) insert(key string, version string, leaf *Node) error {
+	if key == "" || leaf.handler == nil {
+		return nil
+	}
+	segs, err := tr.parse(key)
+	if err != nil {
+		return err
 	}
 	if tr.safe {
 		defer tr.mu.Unlock()
 		tr.mu.Lock()
 	}
-	n := tr.root
+	// Every node on the path is forked, so in-flight lookups keep reading the old root
+	root := tr.root.Load().fork()
+	n := root
+	for _, s := range segs {
+		if s.kind == staticEdge {
+			n = tr.insertStatic(n, s.key)
+		} else if n, err = tr.insertDynamic(n, s); err != nil {
+			return fmt.Errorf("route %s: %w", key, err)
+		}
+	}
+	if len(leaf.middlewares) > 0 {
+		leaf.chained = routeHandler(leaf.handler, leaf.middlewares)
+	}
+	if leaf.group != nil {
+		// Only served once the group has middlewares, so bare group routes keep the pooled path
+		base := leaf.chained
+		if base == nil {
+			base = routeHandler(leaf.handler, nil)
+		}
+		leaf.live = leaf.group.live(base)
+	}
+	if version == "" {
+		if n.handler != nil {
+			return fmt.Errorf("route %s is already registered", key)
+		}
+		n.handler, n.middlewares, n.chained, n.body, n.group, n.live = leaf.handler, leaf.middlewares, leaf.chained, leaf.body, leaf.group, leaf.live
+	} else {
+		if _, ok := n.versions[version]; ok {
+			return fmt.Errorf("route %s is already registered for version %s", key, version)
+		}
+		versions := make(map[string]*Node, len(n.versions)+1)
+		for k, v := range n.versions {
+			versions[k] = v
+		}
+		versions[version] = leaf
+		n.versions = versions
+	}
+	tr.root.Store(root)
+	return nil
+}
 
-	for {
-		var next *edge
-		var slice string
+/**
+ * @info Removes a route with all its versions from the tree, merging nodes left with a single static edge so the tree stays compact
+ * @param {string} [key] The route path used as key
+ * @returns {error}
+ */
+func (tr *tree) RemoveNode(key string) error {
+	segs, err := tr.parse(key)
+	if err != nil {
+		return err
+	}
+	if tr.safe {
+		defer tr.mu.Unlock()
+		tr.mu.Lock()
+	}
+	// Only the path to the route is forked, so in-flight lookups keep reading the old root
+	root := tr.remove(tr.root.Load(), segs)
+	if root == nil {
+		return fmt.Errorf("route %s is not registered", key)
+	}
+	tr.root.Store(root)
+	return nil
+}
 
-		for _, edge := range n.edges {
-			var found int
-			slice = edge.key
-			for i := range slice {
-				if i < len(key) && slice[i] == key[i] {
-					found++
-					continue
-				}
-				break
-			}
-			if found > 0 {
-				key = key[found:]
-				slice = slice[found:]
-				next = edge
-				break
+/**
+ * @info Returns a forked copy of the node without the route below it, nil when the route is not there
+ * @param {*Node} [n] The published node to remove below
+ * @param {[]segment} [segs] The rest of the route segments
+ * @returns {*Node}
+ */
+func (tr *tree) remove(n *Node, segs []segment) *Node {
+	if len(segs) == 0 {
+		if !n.routable() {
+			return nil
+		}
+		c := n.fork()
+		c.handler, c.middlewares, c.chained, c.versions, c.body, c.group, c.live = nil, nil, nil, nil, nil, nil, nil
+		return c
+	}
+	s := segs[0]
+	for i, e := range n.edges {
+		rest := segs
+		if e.kind != s.kind {
+			continue
+		} else if e.kind != staticEdge {
+			if e.key != s.key {
+				continue
 			}
+			rest = segs[1:]
+		} else if !strings.HasPrefix(s.key, e.key) {
+			continue
+		} else if len(s.key) > len(e.key) {
+			// The static segment spans several edges after splits
+			rest = append([]segment{{kind: staticEdge, key: s.key[len(e.key):]}}, segs[1:]...)
+		} else {
+			rest = segs[1:]
+		}
+		child := tr.remove(e.n, rest)
+		if child == nil {
+			return nil
 		}
-		if next != nil {
-			n = next.n
-			n.priority++
+		child.priority--
+		p := n.fork()
+		switch {
+		case !child.routable() && len(child.edges) == 0:
+			p.edges = append(p.edges[:i], p.edges[i+1:]...)
+			tr.len--
+			tr.size -= len(e.key)
+		case !child.routable() && len(child.edges) == 1 && e.kind == staticEdge && child.edges[0].kind == staticEdge:
+			// Folds the node back into a single edge, undoing the split that created it
+			p.edges[i] = &edge{key: e.key + child.edges[0].key, n: child.edges[0].n}
+			tr.len--
+		default:
+			c := *e
+			c.n = child
+			p.edges[i] = &c
+		}
+		return p
+	}
+	return nil
+}
 
-			if len(key) == 0 {
-				if len(slice) == 0 {
-					n.handler = handler
-					return
-				}
-				next.key = next.key[:len(next.key)-len(slice)]
-				c := n.clone()
-				c.priority--
-				n.edges = []*edge{
-					&edge{
-						key: slice,
-						n:   c,
-					},
-				}
-				n.handler = handler
-				tr.len++
-				return
+/**
+ * @info Inserts a static key below a forked node, splitting edges on common prefixes
+ * @param {*Node} [n] The forked node to insert below
+ * @param {string} [key] The static key to insert
+ * @returns {*Node}
+ */
+func (tr *tree) insertStatic(n *Node, key string) *Node {
+	for key != "" {
+		var next *edge
+		for i, e := range n.edges {
+			if e.kind != staticEdge {
+				continue
 			}
-			if len(slice) > 0 {
-				c := n.clone()
-				c.priority--
-				n.edges = []*edge{
-					&edge{ // the suffix that is clone into a new node
-						key: slice,
-						n:   c,
-					},
-					&edge{ // the new node
-						key: key,
-						n: &Node{
-							handler:  handler,
-							depth:    n.depth + 1,
-							priority: 1,
+			found := 0
+			for found < len(e.key) && found < len(key) && e.key[found] == key[found] {
+				found++
+			}
+			if found == 0 {
+				continue
+			}
+			c := *e
+			if found < len(e.key) {
+				c.key = e.key[:found]
+				c.n = &Node{
+					depth:    n.depth + 1,
+					priority: e.n.priority,
+					edges: []*edge{
+						&edge{ // the suffix keeps pointing at the published node
+							key: e.key[found:],
+							n:   e.n,
 						},
 					},
 				}
-				next.key = next.key[:len(next.key)-len(slice)]
-				n.handler = nil
-				tr.len += 2
-				tr.size += len(key)
-				return
+				tr.len++
+			} else {
+				c.n = e.n.fork()
 			}
-			continue
+			n.edges[i] = &c
+			key = key[found:]
+			next = &c
+			break
+		}
+		if next == nil {
+			c := &Node{depth: n.depth + 1}
+			n.addEdge(&edge{key: key, n: c})
+			tr.len++
+			tr.size += len(key)
+			return c
 		}
-		n.edges = append(n.edges, &edge{
-			key: key,
-			n: &Node{
-				handler:  handler,
-				depth:    n.depth + 1,
-				priority: 1,
-			},
-		})
+		n = next.n
+		n.priority++
+	}
+	return n
+}
 
-		tr.len++
-		tr.size += len(key)
-		return
+/**
+ * @info Inserts a param or catch-all segment below a forked node
+ * @param {*Node} [n] The forked node to insert below
+ * @param {segment} [s] The dynamic segment to insert
+ * @returns {*Node, error}
+ */
+func (tr *tree) insertDynamic(n *Node, s segment) (*Node, error) {
+	for i, e := range n.edges {
+		if e.kind != s.kind {
+			continue
+		}
+		if e.key == s.key {
+			c := *e
+			c.n = e.n.fork()
+			c.n.priority++
+			n.edges[i] = &c
+			return c.n, nil
+		}
+		// Siblings only differing by name would match the exact same segments
+		if s.kind == catchAllEdge || e.key[len(e.name)+1:] == s.key[len(s.name)+1:] {
+			return nil, fmt.Errorf("segment %s is ambiguous with %s registered at the same position", s.key, e.key)
+		}
 	}
+	c := &Node{depth: n.depth + 1, priority: 1}
+	n.addEdge(&edge{key: s.key, kind: s.kind, name: s.name, match: s.match, n: c})
+	tr.len++
+	tr.size += len(s.key)
+	return c, nil
 }
 
 /**
- * @info Finds a specific node from the tree
+ * @info Finds a specific node from the tree, appending the captured params
  * @param {string} [key] The route path used as key
- * @returns {*Node, map[string]string}
+ * @param {*Params} [ps] The params to append to, may be nil
+ * @returns {*Node}
  */
-func (tr *tree) GetNode(key string) (*Node, map[string]string) {
+func (tr *tree) GetNode(key string, ps *Params) *Node {
 	if key == "" {
-		return nil, nil
+		return nil
 	}
-	if tr.safe {
-		defer tr.mu.Unlock()
-		tr.mu.Lock()
+	if ps == nil {
+		var scratch Params
+		ps = &scratch
 	}
-	n := tr.root
-	var params map[string]string
-	for n != nil && key != "" {
-		var next *edge
-	Walk:
-		for _, edge := range n.edges {
-			slice := edge.key
-
-			for {
-				pindex := len(slice)
-				if i := strings.IndexByte(slice, tr.placeholder); i >= 0 {
-					pindex = i
-				}
-				prefix := slice[:pindex]
-				if !strings.HasPrefix(key, prefix) {
-					continue Walk
-				}
-				key = key[len(prefix):]
-
-				if len(prefix) == len(slice) {
-					next = edge
-					break Walk
-				}
-				var delimint int
-				slice = slice[pindex:]
-				if delimint = strings.IndexByte(slice[1:], tr.delim) + 1; delimint <= 0 {
-					delimint = len(slice)
-				}
-				k := slice[1:delimint]
-				slice = slice[delimint:]
-				if delimint = strings.IndexByte(key[1:], tr.delim) + 1; delimint <= 0 {
-					delimint = len(key)
-				}
-				if params == nil {
-					params = make(map[string]string)
-				}
-				params[k] = key[:delimint]
-				key = key[delimint:]
-				if slice == "" && key == "" {
-					next = edge
-					break Walk
-				}
+	return tr.match(tr.root.Load(), key, ps)
+}
 
+/**
+ * @info Matches the rest of the key below a node trying static, param then catch-all edges and backtracking when a branch fails
+ * @param {*Node} [n] The node to match below
+ * @param {string} [key] The rest of the route path
+ * @param {*Params} [ps] The params to append to
+ * @returns {*Node}
+ */
+func (tr *tree) match(n *Node, key string, ps *Params) *Node {
+	if key == "" {
+		if n.routable() {
+			return n
+		}
+		// A catch-all also matches an empty remainder
+		for _, e := range n.edges {
+			if e.kind == catchAllEdge && e.n.routable() {
+				*ps = append(*ps, Param{Key: e.name})
+				return e.n
 			}
 		}
-		if next != nil {
-			n = next.n
-			continue
+		return nil
+	}
+	for _, e := range n.edges {
+		switch e.kind {
+		case staticEdge:
+			if strings.HasPrefix(key, e.key) || (tr.fold && len(key) >= len(e.key) && strings.EqualFold(key[:len(e.key)], e.key)) {
+				if found := tr.match(e.n, key[len(e.key):], ps); found != nil {
+					return found
+				}
+			}
+		case paramEdge:
+			end := strings.IndexByte(key, tr.delim)
+			if end < 0 {
+				end = len(key)
+			}
+			if end == 0 || (e.match != nil && !e.match(key[:end])) {
+				continue
+			}
+			mark := len(*ps)
+			*ps = append(*ps, Param{Key: e.name, Value: key[:end]})
+			if found := tr.match(e.n, key[end:], ps); found != nil {
+				return found
+			}
+			*ps = (*ps)[:mark]
+		case catchAllEdge:
+			if e.n.routable() {
+				*ps = append(*ps, Param{Key: e.name, Value: key})
+				return e.n
+			}
 		}
-		n = nil
 	}
-	return n, params
+	return nil
 }
 
 /**
- * @info Turns a radix tree into a hash map
+ * @info Turns a radix tree into a hash map keyed by the full route paths
  * @param {tree} [tre] The tree to convert
  * @returns {map[string]Handler}
  */
 func ToMap(tre *tree) map[string]Handler {
-	ma := make(map[string]Handler, tre.len)
-	for _, edge := range tre.root.edges {
-		ma[edge.key] = edge.n.handler
-	}
+	ma := make(map[string]Handler)
+	tre.root.Load().walk("", func(key string, n *Node) {
+		ma[key] = n.handler
+	})
 	return ma
 }
 
 /**
  * @info Inserts a hash map to the tree
  * @param {map[string]Handler} [m] The hash map to insert
- * @returns {}
+ * @returns {error}
  */
-func (tr *tree) InsertMap(m map[string]Handler) {
+func (tr *tree) InsertMap(m map[string]Handler) error {
 	for i, v := range m {
-		tr.InsertNode(i, v)
+		if err := tr.InsertNode(i, v); err != nil {
+			return err
+		}
 	}
+	return nil
 }
@@ -0,0 +1,255 @@
+package minima
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Builds a tree holding the given routes
+func newTree(t *testing.T, routes ...string) *tree {
+	t.Helper()
+	tr := NewTree()
+	for _, route := range routes {
+		if err := tr.InsertNode(route, noop); err != nil {
+			t.Fatalf("insert %s: %v", route, err)
+		}
+	}
+	return tr
+}
+
+// Finds the registered route a matched node belongs to
+func routeOf(tr *tree, n *Node) string {
+	route := ""
+	tr.root.Load().walk("", func(key string, found *Node) {
+		if found == n {
+			route = key
+		}
+	})
+	return route
+}
+
+type matchTest struct {
+	path   string
+	route  string
+	params string
+}
+
+// Looks every path up, comparing the route found and the params it captured
+func testMatches(t *testing.T, tr *tree, tests []matchTest) {
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var ps Params
+			n := tr.GetNode(tt.path, &ps)
+			route := ""
+			if n != nil {
+				route = routeOf(tr, n)
+			}
+			var params []string
+			for _, p := range ps {
+				params = append(params, p.Key+"="+p.Value)
+			}
+			if route != tt.route || (n != nil && strings.Join(params, ",") != tt.params) {
+				t.Errorf("got %q %v, want %q %s", route, params, tt.route, tt.params)
+			}
+			if n == nil && len(ps) != 0 {
+				t.Errorf("failed match left params %v", params)
+			}
+		})
+	}
+}
+
+func TestTreeCatchAll(t *testing.T) {
+	tr := newTree(t, "/files/*path", "/files/readme", "/static/*")
+	testMatches(t, tr, []matchTest{
+		{"/files/readme", "/files/readme", ""},
+		{"/files/docs/a.txt", "/files/*path", "path=docs/a.txt"},
+		{"/files/readme/old", "/files/*path", "path=readme/old"},
+		{"/files/", "/files/*path", "path="},
+		{"/static/css/app.css", "/static/*", "*=css/app.css"},
+		{"/static", "", ""},
+	})
+}
+
+func TestTreeConstraints(t *testing.T) {
+	tr := newTree(t,
+		"/users/:id<int>/edit",
+		"/orders/:code<[A-Z]{3}>",
+		"/orders/:id<uint>",
+		"/orders/:slug",
+		"/items/:id<uuid>",
+		"/tags/:tag<alpha>",
+		"/keys/:key<alnum>",
+	)
+	testMatches(t, tr, []matchTest{
+		{"/users/42/edit", "/users/:id<int>/edit", "id=42"},
+		{"/users/-7/edit", "/users/:id<int>/edit", "id=-7"},
+		{"/users/abc/edit", "", ""},
+		// Constrained params are tried before the plain one
+		{"/orders/ABC", "/orders/:code<[A-Z]{3}>", "code=ABC"},
+		{"/orders/12", "/orders/:id<uint>", "id=12"},
+		{"/orders/ABCD", "/orders/:slug", "slug=ABCD"},
+		{"/orders/-1", "/orders/:slug", "slug=-1"},
+		{"/items/0b3e5f2a-1c4d-4e6f-8a9b-0c1d2e3f4a5b", "/items/:id<uuid>", "id=0b3e5f2a-1c4d-4e6f-8a9b-0c1d2e3f4a5b"},
+		{"/items/42", "", ""},
+		{"/tags/go", "/tags/:tag<alpha>", "tag=go"},
+		{"/tags/go1", "", ""},
+		{"/keys/go1", "/keys/:key<alnum>", "key=go1"},
+		{"/keys/go-1", "", ""},
+	})
+}
+
+func TestTreeConstraintErrors(t *testing.T) {
+	for _, route := range []string{"/a/:id<[>", "/a/:id<>", "/a/:id<int"} {
+		if err := NewTree().InsertNode(route, noop); err == nil {
+			t.Errorf("%s inserted without error", route)
+		}
+	}
+}
+
+func TestTreeInsertErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []string
+	}{
+		{"duplicate", []string{"/a/:id", "/a/:id"}},
+		{"duplicate static", []string{"/a/b", "/a/b"}},
+		{"params only differing by name", []string{"/a/:id", "/a/:name"}},
+		{"constraints only differing by name", []string{"/a/:id<int>", "/a/:n<int>"}},
+		{"two catch-alls", []string{"/a/*path", "/a/*rest"}},
+		{"catch-all not at the end", []string{"/a/*path/b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTree()
+			var err error
+			for _, route := range tt.routes {
+				if err = tr.InsertNode(route, noop); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				t.Errorf("%v inserted without error", tt.routes)
+			}
+		})
+	}
+}
+
+func TestTreePriority(t *testing.T) {
+	tr := newTree(t,
+		"/users",
+		"/users/new",
+		"/users/:id",
+		"/users/:id<int>/edit",
+		"/users/:id/posts/:post",
+		"/users/:id/profile",
+		"/users/*rest",
+	)
+	testMatches(t, tr, []matchTest{
+		{"/users", "/users", ""},
+		{"/users/new", "/users/new", ""},
+		{"/users/42", "/users/:id", "id=42"},
+		{"/users/ada/posts/9", "/users/:id/posts/:post", "id=ada,post=9"},
+		// The constrained branch has no profile route, so the matcher backtracks into the plain param
+		{"/users/42/profile", "/users/:id/profile", "id=42"},
+		// The static new branch has no posts route, so the matcher backtracks into :id
+		{"/users/new/posts/1", "/users/:id/posts/:post", "id=new,post=1"},
+		// Neither param branch matches, so the catch-all takes the whole rest
+		{"/users/new/edit", "/users/*rest", "rest=new/edit"},
+		{"/users/", "/users/*rest", "rest="},
+	})
+}
+
+func TestTreeFold(t *testing.T) {
+	tr := newTree(t, "/Users/:id", "/posts/new")
+	tr.fold = true
+	for path, route := range map[string]string{
+		"/POSTS/NEW": "/posts/new",
+		"/users/Ada": "/Users/:id",
+	} {
+		if n := tr.GetNode(path, nil); n == nil || routeOf(tr, n) != route {
+			t.Errorf("%s: want %s", path, route)
+		}
+	}
+}
+
+// Prints the shape of the tree, one edge key per line indented by depth
+func dump(n *Node, indent string) string {
+	var b strings.Builder
+	for _, e := range n.edges {
+		mark := ""
+		if e.n.routable() {
+			mark = " *"
+		}
+		fmt.Fprintf(&b, "%s%s%s\n", indent, e.key, mark)
+		b.WriteString(dump(e.n, indent+"  "))
+	}
+	return b.String()
+}
+
+func TestTreeRemove(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []string
+		remove string
+	}{
+		{"leaf split from its sibling", []string{"/users", "/useful"}, "/useful"},
+		{"route holding a split", []string{"/use", "/users", "/useful"}, "/use"},
+		{"middle of a static chain", []string{"/a", "/a/b", "/a/b/c"}, "/a/b"},
+		{"param leaf", []string{"/users/:id", "/users/new"}, "/users/:id"},
+		{"below a param", []string{"/users/:id/posts", "/users/:id/pics"}, "/users/:id/pics"},
+		{"constrained param", []string{"/o/:id<int>", "/o/:slug"}, "/o/:id<int>"},
+		{"catch-all", []string{"/files/*path", "/files/readme"}, "/files/*path"},
+		{"only route", []string{"/users/:id/edit"}, "/users/:id/edit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTree(t, tt.routes...)
+			if err := tr.RemoveNode(tt.remove); err != nil {
+				t.Fatal(err)
+			}
+			var rest []string
+			for _, route := range tt.routes {
+				if route != tt.remove {
+					rest = append(rest, route)
+				}
+			}
+			// Removing has to leave the same shape as never inserting the route
+			want := newTree(t, rest...)
+			if got, exp := dump(tr.root.Load(), ""), dump(want.root.Load(), ""); got != exp {
+				t.Errorf("tree after remove:\n%swant:\n%s", got, exp)
+			}
+			if tr.len != want.len {
+				t.Errorf("len %d, want %d", tr.len, want.len)
+			}
+			var left []string
+			for key := range ToMap(tr) {
+				left = append(left, key)
+			}
+			sort.Strings(left)
+			sort.Strings(rest)
+			if strings.Join(left, " ") != strings.Join(rest, " ") {
+				t.Errorf("routes left %v, want %v", left, rest)
+			}
+			if err := tr.RemoveNode(tt.remove); err == nil {
+				t.Errorf("removing %s twice succeeded", tt.remove)
+			}
+		})
+	}
+}
+
+func TestTreeRemoveKeepsSnapshots(t *testing.T) {
+	tr := newTree(t, "/users", "/useful")
+	old := tr.root.Load()
+	before := dump(old, "")
+	if err := tr.RemoveNode("/useful"); err != nil {
+		t.Fatal(err)
+	}
+	// Readers holding the old root still see every route
+	if dump(old, "") != before || tr.match(old, "/useful", &Params{}) == nil {
+		t.Errorf("remove changed the published tree:\n%s", dump(old, ""))
+	}
+}
@@ -0,0 +1,271 @@
+package minima
+
+/**
+* Minima is a free and open source software under Mit license
+
+Copyright (c) 2024 gominima
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+* Authors @apoorvcodes @megatank58
+* Maintainers @Panquesito7 @savioxavier @Shubhaankar-Sharma @apoorvcodes @megatank58
+* Thank you for showing interest in minima and for this beautiful community
+*/
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/**
+ * @info A field breaking one of the rules of its validate tag
+ * @property {string} [Field] The dotted name of the field
+ * @property {string} [Rule] The broken rule, e.g. required or min
+ * @property {string} [Param] The param of the rule, e.g. 3 for min=3
+ * @property {string} [Message] What is wrong with the value
+ */
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+/**
+ * @info The violations returned by Validate
+ */
+type Violations []Violation
+
+/**
+ * @info Joins the violations into a single message
+ * @returns {string}
+ */
+func (v Violations) Error() string {
+	msgs := make([]string, len(v))
+	for i, f := range v {
+		msgs[i] = fmt.Sprintf("%s %s", f.Field, f.Message)
+	}
+	return "validation failed: " + strings.Join(msgs, ", ")
+}
+
+/**
+ * @info Checks a struct against the validate tags of its fields, e.g. validate:"required,min=3,max=64,email,oneof=a b c", descending into nested structs and slices of structs. Numbers are always checked, other rules than required are skipped for empty strings, empty lists and nil pointers so optional fields can be left out
+ * @param {interface{}} [v] The struct or pointer to struct to check
+ * @returns {error} Violations for the broken rules
+ */
+func Validate(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate expects a struct, got %T", v)
+	}
+	var violations Violations
+	if err := validateStruct(rv, "", &violations); err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
+
+/**
+ * @info Binds the request into a struct and validates it
+ * @param {interface{}} [dst] A pointer to the struct to populate
+ * @returns {error} FieldErrors when binding fails, Violations when validation fails
+ */
+func (r *Request) BindAndValidate(dst interface{}) error {
+	if err := r.Bind(dst); err != nil {
+		return err
+	}
+	return Validate(dst)
+}
+
+/**
+ * @info Checks the fields of a struct value
+ * @param {reflect.Value} [v] The struct value
+ * @param {string} [prefix] The dotted name of the struct
+ * @param {*Violations} [violations] The violations to append to
+ * @returns {error}
+ */
+func validateStruct(v reflect.Value, prefix string, violations *Violations) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() && (!sf.Anonymous || sf.Type.Kind() != reflect.Struct) {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Anonymous && fv.Kind() == reflect.Struct {
+			if err := validateStruct(fv, prefix, violations); err != nil {
+				return err
+			}
+			continue
+		}
+		name := prefix + fieldName(sf)
+		if tag := sf.Tag.Get("validate"); tag != "" && tag != "-" {
+			if err := validateField(fv, name, tag, violations); err != nil {
+				return err
+			}
+		}
+		if err := validateNested(fv, name, violations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/**
+ * @info Descends into nested structs, pointers to structs and slices of structs
+ * @param {reflect.Value} [v] The field value
+ * @param {string} [name] The dotted name of the field
+ * @param {*Violations} [violations] The violations to append to
+ * @returns {error}
+ */
+func validateNested(v reflect.Value, name string, violations *Violations) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return validateNested(v.Elem(), name, violations)
+		}
+	case reflect.Struct:
+		if v.Type() != timeType {
+			return validateStruct(v, name+".", violations)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := validateNested(v.Index(i), fmt.Sprintf("%s[%d]", name, i), violations); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/**
+ * @info Checks a single field against the rules of its validate tag
+ * @param {reflect.Value} [v] The field value
+ * @param {string} [name] The dotted name of the field
+ * @param {string} [tag] The validate tag
+ * @param {*Violations} [violations] The violations to append to
+ * @returns {error}
+ */
+func validateField(v reflect.Value, name string, tag string, violations *Violations) error {
+	if v.IsZero() {
+		for _, rule := range strings.Split(tag, ",") {
+			if strings.TrimSpace(rule) == "required" {
+				*violations = append(*violations, Violation{Field: name, Rule: "required", Message: "is required"})
+				return nil
+			}
+		}
+		if v.Kind() < reflect.Int || v.Kind() > reflect.Float64 {
+			// A zero number is a value, an empty string, list or nil pointer means the field was left out
+			return nil
+		}
+	}
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	for _, rule := range strings.Split(tag, ",") {
+		rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		var msg string
+		switch rule {
+		case "", "required":
+			continue
+		case "min", "max":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return fmt.Errorf("rule %s of field %s needs a number", rule, name)
+			}
+			size, unit, ok := measure(v)
+			if !ok {
+				return fmt.Errorf("rule %s can't check field %s of type %s", rule, name, v.Type())
+			}
+			if rule == "min" && size < limit {
+				msg = "must be at least " + param + unit
+			} else if rule == "max" && size > limit {
+				msg = "must be at most " + param + unit
+			}
+		case "email":
+			if v.Kind() != reflect.String {
+				return fmt.Errorf("rule email can't check field %s of type %s", name, v.Type())
+			}
+			if addr, err := mail.ParseAddress(v.String()); err != nil || addr.Address != v.String() {
+				msg = "must be a valid email address"
+			}
+		case "oneof":
+			options := strings.Fields(param)
+			value := fmt.Sprint(v.Interface())
+			found := false
+			for _, option := range options {
+				found = found || option == value
+			}
+			if !found {
+				msg = "must be one of " + strings.Join(options, ", ")
+			}
+		default:
+			return fmt.Errorf("unknown validation rule %s on field %s", rule, name)
+		}
+		if msg != "" {
+			*violations = append(*violations, Violation{Field: name, Rule: rule, Param: param, Message: msg})
+		}
+	}
+	return nil
+}
+
+/**
+ * @info Measures a value for min and max, the length of strings and lists or the value of numbers
+ * @param {reflect.Value} [v] The value to measure
+ * @returns {float64, string, bool} The size, the unit to report it in and whether the value can be measured
+ */
+func measure(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	}
+	return 0, "", false
+}
+
+/**
+ * @info Names a field after its json or bind tag, falling back to the field name
+ * @param {reflect.StructField} [sf] The struct field
+ * @returns {string}
+ */
+func fieldName(sf reflect.StructField) string {
+	for _, source := range append([]string{"json"}, bindSources...) {
+		if tag, _, _ := strings.Cut(sf.Tag.Get(source), ","); tag != "" && tag != "-" {
+			return tag
+		}
+	}
+	return sf.Name
+}
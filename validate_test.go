@@ -0,0 +1,103 @@
+package minima
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	type item struct {
+		Qty   int     `json:"qty" validate:"min=1,max=10"`
+		Size  string  `json:"size" validate:"oneof=s m l"`
+		Level int     `json:"level" validate:"oneof=1 2 3"`
+		Note  *string `json:"note" validate:"min=3"`
+		Email string  `json:"email" validate:"required,email"`
+	}
+	note := "ok"
+
+	tests := []struct {
+		name  string
+		value item
+		want  []string
+	}{
+		{"valid", item{Qty: 2, Size: "m", Level: 1, Email: "a@b.co"}, nil},
+		{"zero number fails min", item{Qty: 0, Level: 1, Email: "a@b.co"}, []string{"qty min"}},
+		{"zero number fails oneof", item{Qty: 1, Email: "a@b.co"}, []string{"level oneof"}},
+		{"empty string skips oneof", item{Qty: 1, Level: 2, Email: "a@b.co"}, nil},
+		{"over max", item{Qty: 11, Level: 1, Email: "a@b.co"}, []string{"qty max"}},
+		{"pointer checked when set", item{Qty: 1, Level: 1, Note: &note, Email: "a@b.co"}, []string{"note min"}},
+		{"required reported once", item{Qty: 1, Level: 1}, []string{"email required"}},
+		{"bad email", item{Qty: 1, Level: 1, Email: "nope"}, []string{"email email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.value)
+			var got []string
+			var violations Violations
+			if errors.As(err, &violations) {
+				for _, v := range violations {
+					got = append(got, v.Field+" "+v.Rule)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidStatus(t *testing.T) {
+	type user struct {
+		Name string `json:"name" validate:"required,min=3"`
+		Age  int    `json:"age"`
+	}
+	type broken struct {
+		Name string `json:"name" validate:"shiny"`
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		dst    func() interface{}
+		status int
+	}{
+		{"violations", `{"name":"al"}`, func() interface{} { return &user{} }, 422},
+		{"field errors", `{"name":"ada","age":"old"}`, func() interface{} { return &user{} }, 422},
+		{"malformed json", `{"name":`, func() interface{} { return &user{} }, 400},
+		{"unknown rule", `{"name":"ada"}`, func() interface{} { return &broken{} }, 500},
+		{"bad destination", `{}`, func() interface{} { return user{} }, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := Engine()
+			app.Post("/", func(res *Response, req *Request) {
+				if err := req.BindAndValidate(tt.dst()); err != nil {
+					res.Invalid(err)
+					return
+				}
+				res.OK()
+			})
+
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("got %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("content type %q", ct)
+			}
+			if tt.status == http.StatusInternalServerError && strings.Contains(w.Body.String(), "shiny") {
+				t.Errorf("server error leaked to the client: %s", w.Body.String())
+			}
+		})
+	}
+}
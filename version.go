@@ -0,0 +1,153 @@
+package minima
+
+/**
+* Minima is a free and open source software under Mit license
+
+Copyright (c) 2024 gominima
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+* Authors @apoorvcodes @megatank58
+* Maintainers @Panquesito7 @savioxavier @Shubhaankar-Sharma @apoorvcodes @megatank58
+* Thank you for showing interest in minima and for this beautiful community
+*/
+
+import (
+	"net/http"
+	"strings"
+)
+
+/**
+ * @info Creates a group whose routes are only served for an api version, picked by the API-Version header or an Accept vendor type such as application/vnd.acme.v2+json
+ * @param {string} [version] The api version, e.g. 2 or v2
+ * @returns {*Group}
+ */
+func (r *Router) Version(version string) *Group {
+	g := NewGroup("")
+	g.version = normalizeVersion(version)
+	if r.isCache {
+		r.groups = append(r.groups, g)
+	} else {
+		// The core router has nothing to merge groups into later, so routes are added right away
+		g.router = r
+	}
+	return g
+}
+
+/**
+ * @info The Vary header of versioned routes, shared by responses as appending to it copies it
+ */
+var varyVersion = []string{"Accept, API-Version"}
+
+/**
+ * @info Adds the version headers to the Vary header unless an earlier handler already did
+ * @param {http.Header} [h] The response headers
+ * @returns {}
+ */
+func varyOnVersion(h http.Header) {
+	vary := h["Vary"]
+	if len(vary) == 0 {
+		h["Vary"] = varyVersion
+		return
+	}
+	for _, v := range vary {
+		if v == varyVersion[0] {
+			return
+		}
+	}
+	h["Vary"] = append(vary, varyVersion[0])
+}
+
+/**
+ * @info Sets the api version served to requests which don't ask for one
+ * @param {string} [version] The api version, e.g. 2 or v2
+ * @returns {*Router}
+ */
+func (r *Router) DefaultVersion(version string) *Router {
+	r.defaultVersion = normalizeVersion(version)
+	return r
+}
+
+/**
+ * @info Finds the api version a request asks for, falling back to the default version
+ * @param {*http.Request} [rq] The net/http request instance
+ * @returns {string}
+ */
+func (r *Router) requestVersion(rq *http.Request) string {
+	// The canonical key spares Get from canonicalizing it on every request
+	if v := rq.Header.Get("Api-Version"); v != "" {
+		return normalizeVersion(v)
+	}
+	if v := acceptVersion(rq.Header.Get("Accept")); v != "" {
+		return v
+	}
+	return r.defaultVersion
+}
+
+/**
+ * @info Picks the handler of a route for an api version, the unversioned handler serves any version
+ * @param {string} [version] The requested api version
+ * @returns {*Node}
+ */
+func (n *Node) version(version string) *Node {
+	if v, ok := n.versions[version]; ok && version != "" {
+		return v
+	}
+	if n.handler != nil {
+		return n
+	}
+	return nil
+}
+
+/**
+ * @info Strips the spaces and v prefix of a version
+ * @param {string} [version] The version to normalise
+ * @returns {string}
+ */
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') {
+		version = version[1:]
+	}
+	return version
+}
+
+/**
+ * @info Finds the version in the vendor types of an Accept header, e.g. 2 in application/vnd.acme.v2+json
+ * @param {string} [accept] The Accept header
+ * @returns {string}
+ */
+func acceptVersion(accept string) string {
+	for accept != "" {
+		var media string
+		media, accept, _ = strings.Cut(accept, ",")
+		media, _, _ = strings.Cut(media, ";")
+		_, subtype, _ := strings.Cut(strings.TrimSpace(media), "/")
+		if !strings.HasPrefix(subtype, "vnd.") {
+			continue
+		}
+		subtype, _, _ = strings.Cut(subtype, "+")
+		for i := len(subtype) - 2; i > 0; i-- {
+			if subtype[i] == '.' && subtype[i+1] == 'v' && i+2 < len(subtype) && subtype[i+2] >= '0' && subtype[i+2] <= '9' {
+				return subtype[i+2:]
+			}
+		}
+	}
+	return ""
+}